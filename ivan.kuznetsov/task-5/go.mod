@@ -1,5 +1,14 @@
 module github.com/kuzid-17/task-5
 
-go 1.22.7
+go 1.23.0
 
-require golang.org/x/sync v0.6.0
+require (
+	github.com/stretchr/testify v1.11.1
+	golang.org/x/sync v0.6.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+)
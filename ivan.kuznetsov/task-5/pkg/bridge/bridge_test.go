@@ -0,0 +1,193 @@
+package bridge_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/bridge"
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testChanSize   = 4
+	testMessages   = 100
+	testWindow     = 2
+	reconnectDelay = 10 * time.Millisecond
+	testTimeout    = 10 * time.Second
+)
+
+func listen(t *testing.T, addr string) net.Listener {
+	t.Helper()
+
+	var config net.ListenConfig
+
+	listener, err := config.Listen(context.Background(), "tcp", addr)
+	require.NoError(t, err)
+
+	return listener
+}
+
+func start(t *testing.T, conv conveyer.Conveyer[string]) chan error {
+	t.Helper()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conv.Run(context.Background())
+	}()
+
+	return done
+}
+
+func serve(ctx context.Context, server *bridge.Server[string], listener net.Listener) chan error {
+	done := make(chan error, 1)
+
+	go func() {
+		done <- server.Serve(ctx, listener)
+	}()
+
+	return done
+}
+
+func TestPullConnectsTwoConveyers(t *testing.T) {
+	t.Parallel()
+
+	producer := conveyer.New(testChanSize)
+	producer.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+
+	consumer := conveyer.New(testChanSize)
+	consumer.RegisterDecorator(handlers.GenericDecoratorFunc(func(data string) (string, error) {
+		return data + " remotely", nil
+	}), "remote", "result")
+
+	listener := listen(t, "127.0.0.1:0")
+	serverCtx, stopServer := context.WithCancel(context.Background())
+	served := serve(serverCtx, bridge.NewServer(producer, []string{"out"}, bridge.WithWindow(testWindow)), listener)
+
+	producerDone := start(t, producer)
+	consumerDone := start(t, consumer)
+
+	pulled := make(chan error, 1)
+
+	go func() {
+		pulled <- bridge.Pull(context.Background(), listener.Addr().String(), "out", consumer, "remote",
+			bridge.WithWindow(testWindow))
+	}()
+
+	go func() {
+		for i := range testMessages {
+			_ = producer.Send("in", strconv.Itoa(i))
+		}
+	}()
+
+	for i := range testMessages {
+		data, err := consumer.Recv("result")
+		require.NoError(t, err)
+		require.Equal(t, "decorated: "+strconv.Itoa(i)+" remotely", data)
+	}
+
+	require.NoError(t, producer.Shutdown(context.Background()))
+	require.NoError(t, <-producerDone)
+	require.NoError(t, <-pulled, "pull must end gracefully once the remote channel closes")
+
+	require.NoError(t, consumer.Shutdown(context.Background()))
+	require.NoError(t, <-consumerDone)
+
+	stopServer()
+	require.NoError(t, <-served)
+}
+
+func TestPushDeliversAcrossReconnects(t *testing.T) {
+	t.Parallel()
+
+	receiver := conveyer.New(testChanSize)
+	receiver.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+	server := bridge.NewServer(receiver, []string{"in"}, bridge.WithWindow(testWindow))
+
+	listener := listen(t, "127.0.0.1:0")
+	addr := listener.Addr().String()
+	firstCtx, stopFirst := context.WithCancel(context.Background())
+	firstServed := serve(firstCtx, server, listener)
+
+	sender := conveyer.New(testChanSize)
+	sender.RegisterDecorator(handlers.GenericDecoratorFunc(func(data string) (string, error) {
+		return data, nil
+	}), "local", "outbound")
+
+	receiverDone := start(t, receiver)
+	senderDone := start(t, sender)
+
+	pushed := make(chan error, 1)
+
+	go func() {
+		pushed <- bridge.Push(context.Background(), addr, "in", sender, "outbound",
+			bridge.WithWindow(testWindow), bridge.WithBackoff(reconnectDelay, reconnectDelay))
+	}()
+
+	go func() {
+		for i := range testMessages {
+			_ = sender.Send("local", strconv.Itoa(i))
+		}
+
+		_ = sender.Shutdown(context.Background())
+	}()
+
+	seen := make(map[string]struct{}, testMessages)
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	for len(seen) < testMessages/2 {
+		data, err := receiver.RecvContext(ctx, "out")
+		require.NoError(t, err)
+
+		seen[data] = struct{}{}
+	}
+
+	stopFirst()
+	require.NoError(t, <-firstServed)
+
+	secondCtx, stopSecond := context.WithCancel(context.Background())
+	secondServed := serve(secondCtx, server, listen(t, addr))
+
+	for len(seen) < testMessages {
+		data, err := receiver.RecvContext(ctx, "out")
+		require.NoError(t, err)
+
+		seen[data] = struct{}{}
+	}
+
+	for i := range testMessages {
+		require.Contains(t, seen, "decorated: "+strconv.Itoa(i))
+	}
+
+	require.NoError(t, <-pushed, "push must end gracefully once the local channel drains")
+	require.NoError(t, <-senderDone)
+
+	stopSecond()
+	require.NoError(t, <-secondServed)
+
+	require.NoError(t, receiver.Shutdown(context.Background()))
+	require.NoError(t, <-receiverDone)
+}
+
+func TestPullRejectsUnknownChannel(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(testChanSize)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+
+	listener := listen(t, "127.0.0.1:0")
+	ctx, stop := context.WithCancel(context.Background())
+	served := serve(ctx, bridge.NewServer(conv, []string{"out"}), listener)
+
+	err := bridge.Pull(context.Background(), listener.Addr().String(), "secret", conv, "in")
+	require.ErrorIs(t, err, bridge.ErrRemote)
+
+	stop()
+	require.NoError(t, <-served)
+}
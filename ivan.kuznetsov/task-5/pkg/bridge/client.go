@@ -0,0 +1,336 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+)
+
+type session func(ctx context.Context, c *conn) (bool, error)
+
+type pusher[T any] struct {
+	conv     conveyer.Conveyer[T]
+	localID  string
+	remoteID string
+	options  options
+
+	mu       sync.Mutex
+	credit   int
+	inflight []T
+	drained  bool
+	wake     chan struct{}
+}
+
+func Pull[T any](
+	ctx context.Context,
+	addr string,
+	remoteID string,
+	conv conveyer.Conveyer[T],
+	localID string,
+	opts ...Option,
+) error {
+	options := newOptions(opts)
+
+	return reconnect(ctx, addr, hello{Channel: remoteID, Role: rolePull}, options,
+		func(ctx context.Context, c *conn) (bool, error) {
+			return pull(ctx, c, conv, localID, options.window)
+		})
+}
+
+func Push[T any](
+	ctx context.Context,
+	addr string,
+	remoteID string,
+	conv conveyer.Conveyer[T],
+	localID string,
+	opts ...Option,
+) error {
+	pusher := &pusher[T]{
+		conv:     conv,
+		localID:  localID,
+		remoteID: remoteID,
+		options:  newOptions(opts),
+		mu:       sync.Mutex{},
+		credit:   0,
+		inflight: nil,
+		drained:  false,
+		wake:     make(chan struct{}, 1),
+	}
+
+	return reconnect(ctx, addr, hello{Channel: remoteID, Role: rolePush}, pusher.options, pusher.push)
+}
+
+func reconnect(ctx context.Context, addr string, greeting hello, options options, run session) error {
+	delay := options.backoff
+
+	for {
+		c, err := dial(ctx, addr, greeting)
+		if err == nil {
+			delay = options.backoff
+
+			done, err := run(ctx, c)
+			_ = c.close()
+
+			if done || errors.Is(err, ErrRemote) {
+				return err
+			}
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil
+		case <-timer.C:
+		}
+
+		delay = min(delay*2, options.maxBackoff)
+	}
+}
+
+func dial(ctx context.Context, addr string, greeting hello) (*conn, error) {
+	var dialer net.Dialer
+
+	raw, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", addr, err)
+	}
+
+	c := newConn(raw)
+
+	if err := c.writeJSON(frameHello, greeting); err != nil {
+		_ = c.close()
+
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func pull[T any](ctx context.Context, c *conn, conv conveyer.Conveyer[T], localID string, window int) (bool, error) {
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.write(frameClose, nil)
+		_ = c.close()
+	})
+	defer stop()
+
+	if err := c.writeCount(frameCredit, window); err != nil {
+		return false, err
+	}
+
+	for {
+		f, err := c.read()
+		if err != nil {
+			return ctx.Err() != nil, ignoreClosed(ctx, err)
+		}
+
+		switch f.kind {
+		case frameData:
+			data, err := decode[T](f)
+			if err != nil {
+				return true, err
+			}
+
+			if err := conv.SendContext(ctx, localID, data); err != nil {
+				return true, ignoreClosed(ctx, fmt.Errorf("deliver to %q: %w", localID, err))
+			}
+
+			if err := c.writeCount(frameAck, 1); err != nil {
+				return false, err
+			}
+		case frameClose:
+			_ = c.write(frameClose, nil)
+
+			return true, nil
+		case frameError:
+			return true, remoteError(f)
+		default:
+			return false, fmt.Errorf("%w: %d", ErrUnknownFrame, f.kind)
+		}
+	}
+}
+
+func (p *pusher[T]) push(ctx context.Context, c *conn) (bool, error) {
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(sessionCtx, func() {
+		_ = c.close()
+	})
+	defer stop()
+
+	p.mu.Lock()
+	p.credit = 0
+	resend := append([]T{}, p.inflight...)
+	p.mu.Unlock()
+
+	closed := make(chan error, 1)
+
+	go func() {
+		defer cancel()
+
+		closed <- p.read(c)
+	}()
+
+	for {
+		if len(resend) == 0 && p.isDrained() {
+			return p.finish(sessionCtx, c, closed)
+		}
+
+		if !p.await(sessionCtx) {
+			return p.interrupted(ctx, closed)
+		}
+
+		var data T
+
+		if len(resend) > 0 {
+			data, resend = resend[0], resend[1:]
+		} else {
+			next, err := p.conv.RecvContext(sessionCtx, p.localID)
+			if errors.Is(err, conveyer.ErrChanClosed) {
+				p.markDrained()
+
+				continue
+			}
+
+			if err != nil {
+				return p.interrupted(ctx, closed)
+			}
+
+			data = next
+
+			p.mu.Lock()
+			p.inflight = append(p.inflight, data)
+			p.mu.Unlock()
+		}
+
+		p.mu.Lock()
+		p.credit--
+		p.mu.Unlock()
+
+		if err := c.writeJSON(frameData, data); err != nil {
+			return false, err
+		}
+	}
+}
+
+func (p *pusher[T]) read(c *conn) error {
+	for {
+		f, err := c.read()
+		if err != nil {
+			return err
+		}
+
+		switch f.kind {
+		case frameClose:
+			return nil
+		case frameError:
+			return remoteError(f)
+		case frameAck:
+			count := parseCount(f)
+
+			p.mu.Lock()
+			p.inflight = p.inflight[min(count, len(p.inflight)):]
+			p.credit += count
+			p.mu.Unlock()
+		case frameCredit:
+			p.mu.Lock()
+			p.credit += parseCount(f)
+			p.mu.Unlock()
+		default:
+		}
+
+		select {
+		case p.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (p *pusher[T]) await(ctx context.Context) bool {
+	for {
+		p.mu.Lock()
+		credit := p.credit
+		p.mu.Unlock()
+
+		if credit > 0 {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-p.wake:
+		}
+	}
+}
+
+func (p *pusher[T]) finish(ctx context.Context, c *conn, closed chan error) (bool, error) {
+	for {
+		p.mu.Lock()
+		pending := len(p.inflight)
+		p.mu.Unlock()
+
+		if pending == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return p.interrupted(ctx, closed)
+		case <-p.wake:
+		}
+	}
+
+	if err := c.write(frameClose, nil); err != nil {
+		return false, err
+	}
+
+	if err := <-closed; err != nil {
+		return errors.Is(err, ErrRemote), err
+	}
+
+	return true, nil
+}
+
+func (p *pusher[T]) interrupted(ctx context.Context, closed chan error) (bool, error) {
+	if ctx.Err() != nil {
+		return true, nil
+	}
+
+	err := <-closed
+	if errors.Is(err, ErrRemote) {
+		return true, err
+	}
+
+	return false, err
+}
+
+func (p *pusher[T]) isDrained() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.drained
+}
+
+func (p *pusher[T]) markDrained() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.drained = true
+}
+
+func ignoreClosed(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,44 @@
+package bridge
+
+import "time"
+
+const (
+	defaultWindow     = 16
+	defaultBackoff    = 50 * time.Millisecond
+	defaultMaxBackoff = 2 * time.Second
+)
+
+type Option func(*options)
+
+type options struct {
+	window     int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+func WithWindow(window int) Option {
+	return func(opts *options) {
+		opts.window = max(window, 1)
+	}
+}
+
+func WithBackoff(initial time.Duration, maximum time.Duration) Option {
+	return func(opts *options) {
+		opts.backoff = initial
+		opts.maxBackoff = max(initial, maximum)
+	}
+}
+
+func newOptions(opts []Option) options {
+	options := options{
+		window:     defaultWindow,
+		backoff:    defaultBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
@@ -0,0 +1,159 @@
+package bridge
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+var (
+	ErrUnknownChannel = errors.New("channel is not exposed")
+	ErrUnknownFrame   = errors.New("unknown frame type")
+	ErrFrameTooLarge  = errors.New("frame too large")
+	ErrRemote         = errors.New("remote error")
+)
+
+type frameType byte
+
+const (
+	frameHello frameType = iota + 1
+	frameData
+	frameCredit
+	frameAck
+	frameClose
+	frameError
+)
+
+const (
+	frameHeaderSize = 5
+	maxFrameSize    = 16 << 20
+	creditSize      = 4
+)
+
+type role string
+
+const (
+	rolePush role = "push"
+	rolePull role = "pull"
+)
+
+type hello struct {
+	Channel string `json:"channel"`
+	Role    role   `json:"role"`
+}
+
+type frame struct {
+	kind    frameType
+	payload []byte
+}
+
+type conn struct {
+	raw    net.Conn
+	reader *bufio.Reader
+	mu     sync.Mutex
+}
+
+func newConn(raw net.Conn) *conn {
+	return &conn{
+		raw:    raw,
+		reader: bufio.NewReader(raw),
+		mu:     sync.Mutex{},
+	}
+}
+
+func (c *conn) write(kind frameType, payload []byte) error {
+	buf := make([]byte, frameHeaderSize, frameHeaderSize+len(payload))
+	buf[0] = byte(kind)
+	binary.BigEndian.PutUint32(buf[1:], uint32(len(payload)))
+	buf = append(buf, payload...)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.raw.Write(buf); err != nil {
+		return fmt.Errorf("write frame: %w", err)
+	}
+
+	return nil
+}
+
+func (c *conn) read() (frame, error) {
+	header := make([]byte, frameHeaderSize)
+
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return frame{}, fmt.Errorf("read frame: %w", err)
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxFrameSize {
+		return frame{}, fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, size)
+	}
+
+	payload := make([]byte, size)
+
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return frame{}, fmt.Errorf("read frame: %w", err)
+	}
+
+	kind := frameType(header[0])
+	if kind < frameHello || kind > frameError {
+		return frame{}, fmt.Errorf("%w: %d", ErrUnknownFrame, kind)
+	}
+
+	return frame{kind: kind, payload: payload}, nil
+}
+
+func (c *conn) writeJSON(kind frameType, value any) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode frame: %w", err)
+	}
+
+	return c.write(kind, payload)
+}
+
+func (c *conn) writeCount(kind frameType, count int) error {
+	payload := make([]byte, creditSize)
+	binary.BigEndian.PutUint32(payload, uint32(count))
+
+	return c.write(kind, payload)
+}
+
+func (c *conn) writeError(err error) error {
+	return c.write(frameError, []byte(err.Error()))
+}
+
+func (c *conn) close() error {
+	if err := c.raw.Close(); err != nil {
+		return fmt.Errorf("close connection: %w", err)
+	}
+
+	return nil
+}
+
+func parseCount(f frame) int {
+	if len(f.payload) != creditSize {
+		return 0
+	}
+
+	return int(binary.BigEndian.Uint32(f.payload))
+}
+
+func decode[T any](f frame) (T, error) {
+	var data T
+
+	if err := json.Unmarshal(f.payload, &data); err != nil {
+		return data, fmt.Errorf("decode frame: %w", err)
+	}
+
+	return data, nil
+}
+
+func remoteError(f frame) error {
+	return fmt.Errorf("%w: %s", ErrRemote, f.payload)
+}
@@ -0,0 +1,289 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+)
+
+type Server[T any] struct {
+	conv     conveyer.Conveyer[T]
+	channels map[string]*outbox[T]
+	options  options
+}
+
+type outbox[T any] struct {
+	mu        sync.Mutex
+	redeliver []T
+}
+
+type pullSession[T any] struct {
+	mu       sync.Mutex
+	credit   int
+	inflight []T
+	wake     chan struct{}
+}
+
+func NewServer[T any](conv conveyer.Conveyer[T], channels []string, opts ...Option) *Server[T] {
+	server := &Server[T]{
+		conv:     conv,
+		channels: make(map[string]*outbox[T], len(channels)),
+		options:  newOptions(opts),
+	}
+
+	for _, id := range channels {
+		server.channels[id] = &outbox[T]{mu: sync.Mutex{}, redeliver: nil}
+	}
+
+	return server
+}
+
+func (s *Server[T]) ListenAndServe(ctx context.Context, addr string) error {
+	var config net.ListenConfig
+
+	listener, err := config.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	return s.Serve(ctx, listener)
+}
+
+func (s *Server[T]) Serve(ctx context.Context, listener net.Listener) error {
+	stop := context.AfterFunc(ctx, func() {
+		_ = listener.Close()
+	})
+	defer stop()
+
+	var conns sync.WaitGroup
+	defer conns.Wait()
+
+	for {
+		raw, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return fmt.Errorf("accept: %w", err)
+		}
+
+		conns.Add(1)
+
+		go func() {
+			defer conns.Done()
+
+			s.handle(ctx, newConn(raw))
+		}()
+	}
+}
+
+func (s *Server[T]) handle(ctx context.Context, c *conn) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.close()
+	})
+	defer stop()
+
+	greeting, err := c.read()
+	if err != nil || greeting.kind != frameHello {
+		return
+	}
+
+	var h hello
+	if err := json.Unmarshal(greeting.payload, &h); err != nil {
+		_ = c.writeError(fmt.Errorf("decode hello: %w", err))
+
+		return
+	}
+
+	box, ok := s.channels[h.Channel]
+	if !ok {
+		_ = c.writeError(fmt.Errorf("%w: %q", ErrUnknownChannel, h.Channel))
+
+		return
+	}
+
+	switch h.Role {
+	case rolePush:
+		s.servePush(ctx, c, h.Channel)
+	case rolePull:
+		s.servePull(ctx, cancel, c, h.Channel, box)
+	default:
+		_ = c.writeError(fmt.Errorf("%w: role %q", ErrUnknownFrame, h.Role))
+	}
+}
+
+func (s *Server[T]) servePush(ctx context.Context, c *conn, id string) {
+	if err := c.writeCount(frameCredit, s.options.window); err != nil {
+		return
+	}
+
+	for {
+		f, err := c.read()
+		if err != nil {
+			return
+		}
+
+		switch f.kind {
+		case frameData:
+			data, err := decode[T](f)
+			if err != nil {
+				_ = c.writeError(err)
+
+				return
+			}
+
+			if err := s.conv.SendContext(ctx, id, data); err != nil {
+				_ = c.writeError(err)
+
+				return
+			}
+
+			if err := c.writeCount(frameAck, 1); err != nil {
+				return
+			}
+		case frameClose:
+			_ = c.write(frameClose, nil)
+
+			return
+		default:
+			_ = c.writeError(fmt.Errorf("%w: %d", ErrUnknownFrame, f.kind))
+
+			return
+		}
+	}
+}
+
+func (s *Server[T]) servePull(ctx context.Context, cancel context.CancelFunc, c *conn, id string, box *outbox[T]) {
+	session := &pullSession[T]{
+		mu:       sync.Mutex{},
+		credit:   0,
+		inflight: nil,
+		wake:     make(chan struct{}, 1),
+	}
+
+	defer func() {
+		session.mu.Lock()
+		box.requeue(session.inflight)
+		session.mu.Unlock()
+	}()
+
+	readerDone := make(chan struct{})
+
+	go func() {
+		defer close(readerDone)
+		defer cancel()
+
+		session.read(c)
+	}()
+
+	for session.await(ctx) {
+		data, err := box.next(ctx, s.conv, id)
+		if errors.Is(err, conveyer.ErrChanClosed) {
+			_ = c.write(frameClose, nil)
+			<-readerDone
+
+			return
+		}
+
+		if err != nil {
+			return
+		}
+
+		session.mu.Lock()
+		session.credit--
+		session.inflight = append(session.inflight, data)
+		session.mu.Unlock()
+
+		if err := c.writeJSON(frameData, data); err != nil {
+			return
+		}
+	}
+}
+
+func (p *pullSession[T]) read(c *conn) {
+	for {
+		f, err := c.read()
+		if err != nil || f.kind == frameClose {
+			return
+		}
+
+		count := parseCount(f)
+
+		p.mu.Lock()
+
+		switch f.kind {
+		case frameAck:
+			p.inflight = p.inflight[min(count, len(p.inflight)):]
+			p.credit += count
+		case frameCredit:
+			p.credit += count
+		default:
+		}
+
+		p.mu.Unlock()
+
+		select {
+		case p.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (p *pullSession[T]) await(ctx context.Context) bool {
+	for {
+		p.mu.Lock()
+		credit := p.credit
+		p.mu.Unlock()
+
+		if credit > 0 {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-p.wake:
+		}
+	}
+}
+
+func (b *outbox[T]) next(ctx context.Context, conv conveyer.Conveyer[T], id string) (T, error) {
+	b.mu.Lock()
+
+	if len(b.redeliver) > 0 {
+		data := b.redeliver[0]
+		b.redeliver = b.redeliver[1:]
+		b.mu.Unlock()
+
+		return data, nil
+	}
+
+	b.mu.Unlock()
+
+	data, err := conv.RecvContext(ctx, id)
+	if err != nil {
+		return data, fmt.Errorf("recv %q: %w", id, err)
+	}
+
+	return data, nil
+}
+
+func (b *outbox[T]) requeue(inflight []T) {
+	if len(inflight) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.redeliver = append(append([]T{}, inflight...), b.redeliver...)
+}
@@ -0,0 +1,325 @@
+package conveyer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type channel[T any] struct {
+	ch        chan item[T]
+	sealed    chan struct{}
+	sealOnce  sync.Once
+	mu        sync.RWMutex
+	closed    bool
+	writers   atomic.Int32
+	overflow  OverflowPolicy
+	dropped   atomic.Uint64
+	dropMu    sync.Mutex
+	sent      atomic.Uint64
+	received  atomic.Uint64
+	durable   *durableLog[T]
+	external  bool
+	held      bool
+	ttl       time.Duration
+	expired   atomic.Uint64
+	sink      *channel[T]
+	successor atomic.Pointer[channel[T]]
+	removed   atomic.Bool
+	returnMu  sync.Mutex
+	returned  []item[T]
+	wake      chan struct{}
+}
+
+func newChannel[T any](size int) *channel[T] {
+	return &channel[T]{
+		ch:        make(chan item[T], size),
+		sealed:    make(chan struct{}),
+		sealOnce:  sync.Once{},
+		mu:        sync.RWMutex{},
+		closed:    false,
+		writers:   atomic.Int32{},
+		overflow:  OverflowBlock,
+		dropped:   atomic.Uint64{},
+		dropMu:    sync.Mutex{},
+		sent:      atomic.Uint64{},
+		received:  atomic.Uint64{},
+		durable:   nil,
+		external:  false,
+		held:      false,
+		ttl:       0,
+		expired:   atomic.Uint64{},
+		sink:      nil,
+		successor: atomic.Pointer[channel[T]]{},
+		removed:   atomic.Bool{},
+		returnMu:  sync.Mutex{},
+		returned:  nil,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+func (ch *channel[T]) send(ctx context.Context, data T) error {
+	return ch.deliver(ctx, data, time.Time{})
+}
+
+func (ch *channel[T]) deliver(ctx context.Context, data T, deadline time.Time) error {
+	return ch.enqueue(ctx, ch.stamp(data, deadline))
+}
+
+func (ch *channel[T]) enqueue(ctx context.Context, it item[T]) error {
+	ch.mu.RLock()
+	defer ch.mu.RUnlock()
+
+	if ch.closed {
+		return ErrChanClosed
+	}
+
+	select {
+	case <-ch.sealed:
+		return ErrChanClosed
+	default:
+	}
+
+	if ch.durable != nil {
+		return ch.sendDurable(ctx, it)
+	}
+
+	if ch.overflow != OverflowBlock {
+		return ch.offer(it)
+	}
+
+	select {
+	case ch.ch <- it:
+		ch.sent.Add(1)
+
+		return nil
+	case <-ch.sealed:
+		return ErrChanClosed
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrCanceled, ctx.Err())
+	}
+}
+
+func (ch *channel[T]) trySend(data T) error {
+	ch.mu.RLock()
+	defer ch.mu.RUnlock()
+
+	if ch.closed {
+		return ErrChanClosed
+	}
+
+	select {
+	case <-ch.sealed:
+		return ErrChanClosed
+	default:
+	}
+
+	it := ch.stamp(data, time.Time{})
+
+	if ch.durable != nil {
+		return ch.trySendDurable(it)
+	}
+
+	if ch.overflow != OverflowBlock {
+		return ch.offer(it)
+	}
+
+	select {
+	case ch.ch <- it:
+		ch.sent.Add(1)
+
+		return nil
+	default:
+		return ErrChanFull
+	}
+}
+
+func (ch *channel[T]) recv(ctx context.Context) (T, error) {
+	it, ack, err := ch.take(ctx)
+	if err != nil {
+		return it.data, err
+	}
+
+	if ack != nil {
+		return it.data, ack()
+	}
+
+	return it.data, nil
+}
+
+func (ch *channel[T]) tryRecv() (T, error) {
+	var zero T
+
+	if ch.durable != nil {
+		it, offset, err := ch.tryTakeDurable()
+		if err != nil {
+			return zero, err
+		}
+
+		return it.data, ch.durable.ack(offset)
+	}
+
+	for {
+		if it, ok := ch.takeReturned(); ok {
+			if ch.accept(it) {
+				return it.data, nil
+			}
+
+			continue
+		}
+
+		select {
+		case it, ok := <-ch.ch:
+			if !ok {
+				if ch.hasReturned() {
+					continue
+				}
+
+				return zero, ErrChanClosed
+			}
+
+			if ch.accept(it) {
+				return it.data, nil
+			}
+		default:
+			return zero, ErrChanEmpty
+		}
+	}
+}
+
+func (ch *channel[T]) take(ctx context.Context) (item[T], func() error, error) {
+	if ch.durable != nil {
+		it, offset, err := ch.takeDurable(ctx)
+		if err != nil {
+			return it, nil, err
+		}
+
+		return it, func() error { return ch.durable.ack(offset) }, nil
+	}
+
+	var zero item[T]
+
+	for {
+		if it, ok := ch.takeReturned(); ok {
+			if ch.accept(it) {
+				return it, nil, nil
+			}
+
+			continue
+		}
+
+		select {
+		case it, ok := <-ch.ch:
+			if !ok {
+				if ch.hasReturned() {
+					continue
+				}
+
+				return zero, nil, ErrChanClosed
+			}
+
+			if ch.accept(it) {
+				return it, nil, nil
+			}
+		case <-ch.wake:
+		case <-ctx.Done():
+			return zero, nil, fmt.Errorf("%w: %w", ErrCanceled, ctx.Err())
+		}
+	}
+}
+
+func (ch *channel[T]) accept(it item[T]) bool {
+	if it.expiredAt(time.Now()) {
+		ch.expire(it)
+
+		return false
+	}
+
+	ch.received.Add(1)
+
+	return true
+}
+
+func (ch *channel[T]) requeue(it item[T]) {
+	ch.received.Add(^uint64(0))
+	ch.pushFront(it)
+}
+
+func (ch *channel[T]) pushFront(it item[T]) {
+	ch.returnMu.Lock()
+	ch.returned = append(ch.returned, it)
+	ch.returnMu.Unlock()
+
+	ch.signalReturned()
+}
+
+func (ch *channel[T]) takeReturned() (item[T], bool) {
+	ch.returnMu.Lock()
+	defer ch.returnMu.Unlock()
+
+	last := len(ch.returned) - 1
+	if last < 0 {
+		var zero item[T]
+
+		return zero, false
+	}
+
+	it := ch.returned[last]
+	ch.returned = ch.returned[:last]
+
+	if last > 0 {
+		ch.signalReturned()
+	}
+
+	return it, true
+}
+
+func (ch *channel[T]) hasReturned() bool {
+	ch.returnMu.Lock()
+	defer ch.returnMu.Unlock()
+
+	return len(ch.returned) > 0
+}
+
+func (ch *channel[T]) signalReturned() {
+	select {
+	case ch.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (ch *channel[T]) seal() bool {
+	sealed := false
+
+	ch.sealOnce.Do(func() {
+		close(ch.sealed)
+
+		sealed = true
+	})
+
+	return sealed
+}
+
+func (ch *channel[T]) close() {
+	ch.seal()
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	if !ch.closed {
+		ch.closed = true
+		close(ch.ch)
+
+		if ch.durable != nil {
+			_ = ch.durable.close()
+		}
+	}
+}
+
+func (ch *channel[T]) release() {
+	if ch.writers.Add(-1) == 0 {
+		ch.close()
+	}
+}
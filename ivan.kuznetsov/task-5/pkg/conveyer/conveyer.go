@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 )
@@ -13,66 +15,129 @@ var (
 	ErrAlreadyStarted     = errors.New("already started")
 	ErrUnknownHandlerType = errors.New("unknown handler type")
 	ErrChanNotFound       = errors.New("chan not found")
+	ErrChanClosed         = errors.New("chan closed")
+	ErrChanFull           = errors.New("chan is full")
+	ErrChanEmpty          = errors.New("chan is empty")
+	ErrCanceled           = errors.New("operation canceled")
+	ErrNotStarted         = errors.New("not started")
 )
 
-func New(size int) *conveyerImpl {
-	return &conveyerImpl{
-		size:     size,
-		mu:       sync.RWMutex{},
-		chans:    make(map[string]chan string),
-		handlers: []handler{},
-		started:  false,
-	}
+type Conveyer[T any] interface {
+	RegisterDecorator(
+		fn func(context.Context, chan T, chan T) error, input string, output string, opts ...HandlerOption)
+	RegisterMultiplexer(
+		fn func(context.Context, []chan T, chan T) error, inputs []string, output string, opts ...HandlerOption)
+	RegisterSeparator(
+		fn func(context.Context, chan T, []chan T) error, input string, outputs []string, opts ...HandlerOption)
+	RegisterStage(
+		fn func(context.Context, []chan T, []chan T) error, inputs []string, outputs []string, opts ...HandlerOption)
+	DeclareInputs(ids ...string)
+	DeclareOutputs(ids ...string)
+	Validate() error
+	Run(ctx context.Context) error
+	Shutdown(ctx context.Context) error
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+	Wait() error
+	State() State
+	Subscribe(ctx context.Context) <-chan Transition
+	DroppedTransitions() uint64
+	RemoveHandler(ctx context.Context, name string) error
+	RemoveChannel(id string) ([]T, error)
+	Send(id string, data T) error
+	SendContext(ctx context.Context, id string, data T) error
+	TrySend(id string, data T) error
+	Recv(id string) (T, error)
+	RecvContext(ctx context.Context, id string) (T, error)
+	TryRecv(id string) (T, error)
+	Sender(id string) (*Sender[T], error)
+	Receiver(id string) (*Receiver[T], error)
+	ConfigureChannel(id string, opts ...ChannelOption) error
+	Dropped(id string) (uint64, error)
+	Stats() Stats
+	Describe() Graph
+	Use(middlewares ...Middleware)
+	Supervise(strategy Strategy, opts ...SupervisorOption) <-chan SupervisorEvent
+	All(id string) iter.Seq[T]
+	Drain(ctx context.Context, id string) ([]T, error)
+	RecvDeadLetter(id string) (DeadLetter[T], error)
+	SendTTL(ctx context.Context, id string, data T, ttl time.Duration) error
+	RecvExpired(id string) (T, error)
 }
 
-func (c *conveyerImpl) Run(ctx context.Context) error {
-	c.mu.Lock()
-	if c.started {
-		c.mu.Unlock()
+func New(size int) *conveyerImpl[string] {
+	return NewTyped[string](size)
+}
 
-		return ErrAlreadyStarted
+func NewTyped[T any](size int) *conveyerImpl[T] {
+	return &conveyerImpl[T]{
+		size:        size,
+		mu:          sync.RWMutex{},
+		chans:       make(map[string]*channel[T]),
+		channelOpts: make(map[string]channelOptions),
+		deadLetters: make(map[string]*channel[T]),
+		expired:     make(map[string]*channel[T]),
+		handlers:    []handler[T]{},
+		inputs:      make(map[string]struct{}),
+		outputs:     make(map[string]struct{}),
+		state:       StateCreated,
+		finished:    nil,
+		cancel:      nil,
+		runErr:      nil,
+		subscribers: nil,
+		lagged:      0,
+		execution:   nil,
+		middlewares: nil,
+		supervisor: supervisorConfig{
+			strategy:    StrategyNone,
+			backoff:     defaultRestartBackoff,
+			maxBackoff:  defaultRestartMaxBackoff,
+			maxRestarts: defaultMaxRestarts,
+			window:      defaultRestartWindow,
+		},
+		events: nil,
 	}
+}
 
-	c.started = true
-	c.mu.Unlock()
+func (c *conveyerImpl[T]) Run(ctx context.Context) error {
+	run, err := c.start(ctx)
+	if err != nil {
+		return err
+	}
 
+	return run()
+}
+
+func (c *conveyerImpl[T]) execute(ctx context.Context) *errgroup.Group {
 	group, ctx := errgroup.WithContext(ctx)
 
-	for _, handl := range c.handlers {
-		group.Go(func() error {
-			c.mu.RLock()
+	c.startReplays(ctx, group)
+	c.execution = newExecution(ctx, group, c.supervisor)
+	c.execution.keepAlive()
 
-			inChans := make([]chan string, len(handl.inputIDs))
-			outChans := make([]chan string, len(handl.outputIDs))
+	for _, handl := range c.handlers {
+		c.launch(handl)
+	}
 
-			for i, id := range handl.inputIDs {
-				inChans[i] = c.chans[id]
-			}
+	return group
+}
 
-			for i, id := range handl.outputIDs {
-				outChans[i] = c.chans[id]
-			}
+func (c *conveyerImpl[T]) await(group *errgroup.Group) error {
+	err := group.Wait()
 
-			c.mu.RUnlock()
+	c.mu.Lock()
+	c.execution = nil
 
-			switch handl.kind {
-			case hDecorator:
-				return handl.fnDecorator(ctx, inChans[0], outChans[0])
-			case hMultiplexer:
-				return handl.fnMultiplexer(ctx, inChans, outChans[0])
-			case hSeparator:
-				return handl.fnSeparator(ctx, inChans[0], outChans)
-			default:
-				return ErrUnknownHandlerType
-			}
-		})
+	for _, ch := range c.chans {
+		ch.close()
 	}
 
-	err := group.Wait()
+	for _, ch := range c.deadLetters {
+		ch.close()
+	}
 
-	c.mu.Lock()
-	for _, ch := range c.chans {
-		close(ch)
+	for _, ch := range c.expired {
+		ch.close()
 	}
 	c.mu.Unlock()
 
@@ -83,33 +148,113 @@ func (c *conveyerImpl) Run(ctx context.Context) error {
 	return nil
 }
 
-func (c *conveyerImpl) Send(id string, data string) error {
-	c.mu.RLock()
-	channel, okey := c.chans[id]
-	c.mu.RUnlock()
+func runHandler[T any](ctx context.Context, handl handler[T], inChans []chan T, outChans []chan T) error {
+	switch handl.kind {
+	case hDecorator:
+		return handl.fnDecorator(ctx, inChans[0], outChans[0])
+	case hMultiplexer:
+		return handl.fnMultiplexer(ctx, inChans, outChans[0])
+	case hSeparator:
+		return handl.fnSeparator(ctx, inChans[0], outChans)
+	case hStage:
+		return handl.fnStage(ctx, inChans, outChans)
+	default:
+		return ErrUnknownHandlerType
+	}
+}
+
+func (c *conveyerImpl[T]) Shutdown(ctx context.Context) error {
+	return c.Stop(ctx)
+}
+
+func (c *conveyerImpl[T]) countWriters() {
+	topo := c.buildTopology()
 
-	if !okey {
-		return ErrChanNotFound
+	for _, id := range topo.channels {
+		writers := int32(len(uniqueHandlers(topo.producers[id])))
+		c.chans[id].external = c.isInput(topo, id)
+		c.chans[id].held = false
+		if c.chans[id].external {
+			writers++
+		}
+
+		if c.chans[id].hasBacklog() {
+			writers++
+		}
+
+		c.chans[id].writers.Store(writers)
 	}
+}
 
-	channel <- data
+func uniqueHandlers(indexes []int) map[int]struct{} {
+	unique := make(map[int]struct{}, len(indexes))
 
-	return nil
+	for _, idx := range indexes {
+		unique[idx] = struct{}{}
+	}
+
+	return unique
 }
 
-func (c *conveyerImpl) Recv(id string) (string, error) {
+func (c *conveyerImpl[T]) lookup(id string) (*channel[T], error) {
 	c.mu.RLock()
-	channel, okey := c.chans[id]
-	c.mu.RUnlock()
+	defer c.mu.RUnlock()
+
+	if channel, ok := c.chans[id]; ok {
+		return channel, nil
+	}
 
-	if !okey {
-		return "", ErrChanNotFound
+	if channel, ok := c.deadLetters[id]; ok {
+		return channel, nil
 	}
 
-	v, okey := <-channel
-	if !okey {
-		return "undefined", nil
+	return nil, ErrChanNotFound
+}
+
+func (c *conveyerImpl[T]) SendContext(ctx context.Context, id string, data T) error {
+	channel, err := c.lookup(id)
+	if err != nil {
+		return err
 	}
 
-	return v, nil
+	return channel.send(ctx, data)
+}
+
+func (c *conveyerImpl[T]) TrySend(id string, data T) error {
+	channel, err := c.lookup(id)
+	if err != nil {
+		return err
+	}
+
+	return channel.trySend(data)
+}
+
+func (c *conveyerImpl[T]) RecvContext(ctx context.Context, id string) (T, error) {
+	channel, err := c.lookup(id)
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return channel.recv(ctx)
+}
+
+func (c *conveyerImpl[T]) TryRecv(id string) (T, error) {
+	channel, err := c.lookup(id)
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return channel.tryRecv()
+}
+
+func (c *conveyerImpl[T]) Send(id string, data T) error {
+	return c.SendContext(context.Background(), id, data)
+}
+
+func (c *conveyerImpl[T]) Recv(id string) (T, error) {
+	return c.RecvContext(context.Background(), id)
 }
@@ -0,0 +1,237 @@
+package conveyer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	conveyerChanSize = 4
+	recvWait         = 10 * time.Millisecond
+	portsChanSize    = 128
+)
+
+type point struct {
+	X int
+	Y int
+}
+
+func doubleDecorator(ctx context.Context, input chan int, output chan int) error {
+	for data := range input {
+		select {
+		case <-ctx.Done():
+			return nil
+		case output <- data * 2:
+		}
+	}
+
+	return nil
+}
+
+func sumMultiplexer(ctx context.Context, inputs []chan point, output chan point) error {
+	for {
+		var sum point
+
+		for _, input := range inputs {
+			data, ok := <-input
+			if !ok {
+				return nil
+			}
+
+			sum.X += data.X
+			sum.Y += data.Y
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case output <- sum:
+		}
+	}
+}
+
+func TestTypedConveyerCarriesIntegers(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.NewTyped[int](conveyerChanSize)
+	conv.RegisterDecorator(doubleDecorator, "in", "out")
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conv.Run(context.Background())
+	}()
+
+	for _, value := range []int{1, 2, 3} {
+		require.NoError(t, conv.Send("in", value))
+
+		data, err := conv.Recv("out")
+		require.NoError(t, err)
+		require.Equal(t, value*2, data)
+	}
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestTypedConveyerCarriesStructs(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.NewTyped[point](conveyerChanSize)
+	conv.RegisterMultiplexer(sumMultiplexer, []string{"a", "b"}, "sum")
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conv.Run(context.Background())
+	}()
+
+	require.NoError(t, conv.Send("a", point{X: 1, Y: 2}))
+	require.NoError(t, conv.Send("b", point{X: 10, Y: 20}))
+
+	data, err := conv.Recv("sum")
+	require.NoError(t, err)
+	require.Equal(t, point{X: 11, Y: 22}, data)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestShutdownDeliversBufferedDataBeforeClosing(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(conveyerChanSize)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "mid")
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "mid", "out")
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conv.Run(context.Background())
+	}()
+
+	sent := []string{"a", "b", "c"}
+	for _, data := range sent {
+		require.NoError(t, conv.Send("in", data))
+	}
+
+	received, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: "+sent[0], received)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+
+	for _, data := range sent[1:] {
+		received, err := conv.Recv("out")
+		require.NoError(t, err)
+		require.Equal(t, "decorated: "+data, received)
+	}
+
+	_, err = conv.Recv("out")
+	require.ErrorIs(t, err, conveyer.ErrChanClosed)
+	require.ErrorIs(t, conv.Send("in", "late"), conveyer.ErrChanClosed)
+	require.ErrorIs(t, conv.Send("mid", "late"), conveyer.ErrChanClosed)
+}
+
+func TestSendAndRecvVariantsReportTypedErrors(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(1)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+
+	require.NoError(t, conv.TrySend("in", "a"))
+	require.ErrorIs(t, conv.TrySend("in", "b"), conveyer.ErrChanFull)
+	require.ErrorIs(t, conv.TrySend("missing", "b"), conveyer.ErrChanNotFound)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := conv.SendContext(canceled, "in", "b")
+	require.ErrorIs(t, err, conveyer.ErrCanceled)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = conv.TryRecv("out")
+	require.ErrorIs(t, err, conveyer.ErrChanEmpty)
+
+	_, err = conv.TryRecv("missing")
+	require.ErrorIs(t, err, conveyer.ErrChanNotFound)
+
+	timeout, stop := context.WithTimeout(context.Background(), recvWait)
+	defer stop()
+
+	_, err = conv.RecvContext(timeout, "out")
+	require.ErrorIs(t, err, conveyer.ErrCanceled)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	data, err := conv.TryRecv("in")
+	require.NoError(t, err)
+	require.Equal(t, "a", data)
+
+	require.NoError(t, conv.SendContext(context.Background(), "in", "c"))
+
+	data, err = conv.RecvContext(context.Background(), "in")
+	require.NoError(t, err)
+	require.Equal(t, "c", data)
+}
+
+func BenchmarkDecoratorPorts(b *testing.B) {
+	b.Run("bare-channels", func(b *testing.B) {
+		input := make(chan string, portsChanSize)
+		output := make(chan string, portsChanSize)
+		done := make(chan error, 1)
+
+		go func() {
+			done <- handlers.PrefixDecoratorFunc(context.Background(), input, output)
+		}()
+
+		b.ResetTimer()
+
+		go func() {
+			for range b.N {
+				input <- "data"
+			}
+
+			close(input)
+		}()
+
+		for range b.N {
+			<-output
+		}
+
+		b.StopTimer()
+
+		if err := <-done; err != nil {
+			b.Fatal(err)
+		}
+	})
+
+	b.Run("conveyer-ports", func(b *testing.B) {
+		conv := conveyer.New(portsChanSize)
+		conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+
+		if err := conv.Start(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+
+		b.ResetTimer()
+		pumpMessages(b, conv, b.N)
+
+		for range b.N {
+			if _, err := conv.Recv("out"); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		b.StopTimer()
+
+		if err := conv.Stop(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,277 @@
+package conveyer
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Graph struct {
+	Handlers []HandlerNode
+	Channels []ChannelEdge
+	Live     bool
+}
+
+type HandlerNode struct {
+	Name     string
+	Kind     string
+	Inputs   []string
+	Outputs  []string
+	Replicas int
+}
+
+type ChannelEdge struct {
+	ID       string
+	From     []string
+	To       []string
+	Input    bool
+	Output   bool
+	Capacity int
+	Overflow string
+	Durable  bool
+	Depth    int
+}
+
+const (
+	dotFullColor     = "red"
+	mermaidFullStyle = "stroke:red,stroke-width:2px"
+)
+
+func (c *conveyerImpl[T]) Describe() Graph {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	topo := c.buildTopology()
+	graph := Graph{
+		Handlers: make([]HandlerNode, 0, len(c.handlers)),
+		Channels: make([]ChannelEdge, 0, len(topo.channels)),
+		Live:     false,
+	}
+
+	for _, handl := range c.handlers {
+		graph.Handlers = append(graph.Handlers, HandlerNode{
+			Name:     handl.name,
+			Kind:     handl.kind.String(),
+			Inputs:   append([]string{}, handl.inputIDs...),
+			Outputs:  append([]string{}, handl.outputIDs...),
+			Replicas: handl.replicas(),
+		})
+	}
+
+	for _, id := range topo.channels {
+		options := c.channelOptionsFor(id)
+		_, declaredOutput := c.outputs[id]
+
+		graph.Channels = append(graph.Channels, ChannelEdge{
+			ID:       id,
+			From:     c.handlerNames(uniqueHandlers(topo.producers[id])),
+			To:       c.handlerNames(uniqueHandlers(topo.consumers[id])),
+			Input:    c.isInput(topo, id),
+			Output:   declaredOutput || (!c.strict() && len(topo.consumers[id]) == 0),
+			Capacity: cap(c.chans[id].ch),
+			Overflow: options.overflow.String(),
+			Durable:  c.chans[id].durable != nil,
+			Depth:    0,
+		})
+	}
+
+	return graph
+}
+
+func (c *conveyerImpl[T]) handlerNames(indexes map[int]struct{}) []string {
+	names := make([]string, 0, len(indexes))
+
+	for idx, handl := range c.handlers {
+		if _, ok := indexes[idx]; ok {
+			names = append(names, handl.name)
+		}
+	}
+
+	return names
+}
+
+func (g Graph) WithStats(stats Stats) Graph {
+	live := g
+	live.Live = true
+	live.Channels = append([]ChannelEdge{}, g.Channels...)
+
+	for i, edge := range live.Channels {
+		if channelStats, ok := stats.Channels[edge.ID]; ok {
+			live.Channels[i].Depth = channelStats.Depth
+			live.Channels[i].Capacity = channelStats.Capacity
+		}
+	}
+
+	return live
+}
+
+func (e ChannelEdge) label(live bool) string {
+	var label strings.Builder
+
+	label.WriteString(e.ID)
+
+	if live {
+		fmt.Fprintf(&label, " %d/%d", e.Depth, e.Capacity)
+	} else {
+		fmt.Fprintf(&label, " cap %d", e.Capacity)
+	}
+
+	if e.Overflow != OverflowBlock.String() {
+		label.WriteString(" " + e.Overflow)
+	}
+
+	if e.Durable {
+		label.WriteString(" durable")
+	}
+
+	return label.String()
+}
+
+func (e ChannelEdge) full(live bool) bool {
+	return live && e.Depth >= e.Capacity && e.Capacity > 0
+}
+
+func (n HandlerNode) label() string {
+	if n.Replicas > 1 {
+		return fmt.Sprintf("%s\n%s x%d", n.Name, n.Kind, n.Replicas)
+	}
+
+	return n.Name + "\n" + n.Kind
+}
+
+type graphEdge struct {
+	from    string
+	to      string
+	channel ChannelEdge
+}
+
+type graphNodes struct {
+	handlers map[string]string
+	sources  map[string]string
+	sinks    map[string]string
+}
+
+func (g Graph) layout() (graphNodes, []graphEdge) {
+	nodes := graphNodes{
+		handlers: make(map[string]string, len(g.Handlers)),
+		sources:  make(map[string]string),
+		sinks:    make(map[string]string),
+	}
+
+	for i, handl := range g.Handlers {
+		nodes.handlers[handl.Name] = fmt.Sprintf("h%d", i)
+	}
+
+	var edges []graphEdge
+
+	for i, channel := range g.Channels {
+		from := make([]string, 0, len(channel.From)+1)
+		to := make([]string, 0, len(channel.To)+1)
+
+		for _, name := range channel.From {
+			from = append(from, nodes.handlers[name])
+		}
+
+		for _, name := range channel.To {
+			to = append(to, nodes.handlers[name])
+		}
+
+		if channel.Input {
+			nodes.sources[channel.ID] = fmt.Sprintf("in%d", i)
+			from = append(from, nodes.sources[channel.ID])
+		}
+
+		if channel.Output {
+			nodes.sinks[channel.ID] = fmt.Sprintf("out%d", i)
+			to = append(to, nodes.sinks[channel.ID])
+		}
+
+		for _, source := range from {
+			for _, target := range to {
+				edges = append(edges, graphEdge{from: source, to: target, channel: channel})
+			}
+		}
+	}
+
+	return nodes, edges
+}
+
+func RenderDOT(g Graph) string {
+	nodes, edges := g.layout()
+
+	var out strings.Builder
+
+	out.WriteString("digraph conveyer {\n\trankdir=LR;\n\tnode [shape=box];\n")
+
+	for _, handl := range g.Handlers {
+		fmt.Fprintf(&out, "\t%s [label=%s];\n", nodes.handlers[handl.Name], dotQuote(handl.label()))
+	}
+
+	for _, channel := range g.Channels {
+		if id, ok := nodes.sources[channel.ID]; ok {
+			fmt.Fprintf(&out, "\t%s [label=%s, shape=circle];\n", id, dotQuote(channel.ID))
+		}
+
+		if id, ok := nodes.sinks[channel.ID]; ok {
+			fmt.Fprintf(&out, "\t%s [label=%s, shape=doublecircle];\n", id, dotQuote(channel.ID))
+		}
+	}
+
+	for _, edge := range edges {
+		attrs := "label=" + dotQuote(edge.channel.label(g.Live))
+		if edge.channel.full(g.Live) {
+			attrs += ", color=" + dotFullColor
+		}
+
+		fmt.Fprintf(&out, "\t%s -> %s [%s];\n", edge.from, edge.to, attrs)
+	}
+
+	out.WriteString("}\n")
+
+	return out.String()
+}
+
+func RenderMermaid(g Graph) string {
+	nodes, edges := g.layout()
+
+	var out strings.Builder
+
+	out.WriteString("flowchart LR\n")
+
+	for _, handl := range g.Handlers {
+		fmt.Fprintf(&out, "\t%s[%s]\n", nodes.handlers[handl.Name], mermaidQuote(handl.label()))
+	}
+
+	for _, channel := range g.Channels {
+		if id, ok := nodes.sources[channel.ID]; ok {
+			fmt.Fprintf(&out, "\t%s((%s))\n", id, mermaidQuote(channel.ID))
+		}
+
+		if id, ok := nodes.sinks[channel.ID]; ok {
+			fmt.Fprintf(&out, "\t%s(((%s)))\n", id, mermaidQuote(channel.ID))
+		}
+	}
+
+	for _, edge := range edges {
+		fmt.Fprintf(&out, "\t%s -->|%s| %s\n", edge.from, mermaidQuote(edge.channel.label(g.Live)), edge.to)
+	}
+
+	for i, edge := range edges {
+		if edge.channel.full(g.Live) {
+			fmt.Fprintf(&out, "\tlinkStyle %d %s\n", i, mermaidFullStyle)
+		}
+	}
+
+	return out.String()
+}
+
+func dotQuote(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	return `"` + replacer.Replace(value) + `"`
+}
+
+func mermaidQuote(value string) string {
+	replacer := strings.NewReplacer(`"`, "#quot;", "\n", "<br/>")
+
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,98 @@
+package conveyer_test
+
+import (
+	"testing"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const describeChanSize = 2
+
+func describedConveyer(t *testing.T) conveyer.Conveyer[string] {
+	t.Helper()
+
+	conv := conveyer.New(describeChanSize)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "mid",
+		conveyer.WithName("prefix"), conveyer.WithReplicas(2))
+	conv.RegisterSeparator(handlers.SeparatorFunc, "mid", []string{"left", "right"}, conveyer.WithName("split"))
+	require.NoError(t, conv.ConfigureChannel("right", conveyer.WithOverflow(conveyer.OverflowDropOldest)))
+
+	return conv
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+
+	graph := describedConveyer(t).Describe()
+
+	require.False(t, graph.Live)
+	require.Equal(t, []conveyer.HandlerNode{
+		{Name: "prefix", Kind: "decorator", Inputs: []string{"in"}, Outputs: []string{"mid"}, Replicas: 2},
+		{Name: "split", Kind: "separator", Inputs: []string{"mid"}, Outputs: []string{"left", "right"}, Replicas: 1},
+	}, graph.Handlers)
+	require.Equal(t, conveyer.ChannelEdge{
+		ID:       "mid",
+		From:     []string{"prefix"},
+		To:       []string{"split"},
+		Input:    false,
+		Output:   false,
+		Capacity: describeChanSize,
+		Overflow: "block",
+		Durable:  false,
+		Depth:    0,
+	}, graph.Channels[2])
+	require.True(t, graph.Channels[0].Input)
+	require.True(t, graph.Channels[1].Output)
+}
+
+func TestRenderDOT(t *testing.T) {
+	t.Parallel()
+
+	conv := describedConveyer(t)
+	require.NoError(t, conv.Send("in", "a"))
+	require.NoError(t, conv.Send("in", "b"))
+
+	require.Equal(t, `digraph conveyer {
+	rankdir=LR;
+	node [shape=box];
+	h0 [label="prefix\ndecorator x2"];
+	h1 [label="split\nseparator"];
+	in0 [label="in", shape=circle];
+	out1 [label="left", shape=doublecircle];
+	out3 [label="right", shape=doublecircle];
+	in0 -> h0 [label="in 2/2", color=red];
+	h1 -> out1 [label="left 0/2"];
+	h0 -> h1 [label="mid 0/2"];
+	h1 -> out3 [label="right 0/2 drop-oldest"];
+}
+`, conveyer.RenderDOT(conv.Describe().WithStats(conv.Stats())))
+}
+
+func TestRenderMermaid(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, `flowchart LR
+	h0["prefix<br/>decorator x2"]
+	h1["split<br/>separator"]
+	in0(("in"))
+	out1((("left")))
+	out3((("right")))
+	in0 -->|"in cap 2"| h0
+	h1 -->|"left cap 2"| out1
+	h0 -->|"mid cap 2"| h1
+	h1 -->|"right cap 2 drop-oldest"| out3
+`, conveyer.RenderMermaid(describedConveyer(t).Describe()))
+}
+
+func TestDescribeReportsOneReplicaForNonDecorators(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(describeChanSize)
+	conv.RegisterSeparator(handlers.SeparatorFunc, "in", []string{"left", "right"},
+		conveyer.WithName("split"), conveyer.WithReplicas(2))
+
+	require.Equal(t, 1, conv.Describe().Handlers[0].Replicas)
+	require.NotContains(t, conveyer.RenderDOT(conv.Describe()), "x2")
+}
@@ -0,0 +1,599 @@
+package conveyer
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrDurableOverflow = errors.New("durable channels only support the block overflow policy")
+	ErrCorruptSegment  = errors.New("corrupt segment")
+)
+
+const (
+	segmentMaxBytes  = 1 << 20
+	segmentPrefix    = "segment-"
+	segmentSuffix    = ".log"
+	segmentNameWidth = 20
+	ackFileName      = "ack"
+	recordHeaderSize = 8
+	logFileMode      = 0o600
+	logDirMode       = 0o750
+)
+
+type logRecord[T any] struct {
+	offset uint64
+	data   T
+}
+
+type durableLog[T any] struct {
+	dir string
+
+	mu         sync.Mutex
+	segments   []uint64
+	active     *os.File
+	activeSize int64
+	next       uint64
+	watermark  uint64
+	acked      map[uint64]struct{}
+	backlog    []logRecord[T]
+
+	sendTurn chan struct{}
+	recvTurn chan struct{}
+	offsets  chan uint64
+}
+
+func WithDurable(dir string) ChannelOption {
+	return func(opts *channelOptions) {
+		opts.durableDir = dir
+	}
+}
+
+func openDurableLog[T any](dir string, capacity int) (*durableLog[T], error) {
+	if err := os.MkdirAll(dir, logDirMode); err != nil {
+		return nil, fmt.Errorf("create log dir: %w", err)
+	}
+
+	log := &durableLog[T]{
+		dir:        dir,
+		mu:         sync.Mutex{},
+		segments:   nil,
+		active:     nil,
+		activeSize: 0,
+		next:       0,
+		watermark:  0,
+		acked:      make(map[uint64]struct{}),
+		backlog:    nil,
+		sendTurn:   make(chan struct{}, 1),
+		recvTurn:   make(chan struct{}, 1),
+		offsets:    make(chan uint64, capacity+1),
+	}
+
+	if err := log.load(); err != nil {
+		_ = log.closeFile()
+
+		return nil, err
+	}
+
+	return log, nil
+}
+
+func (l *durableLog[T]) load() error {
+	watermark, err := readWatermark(filepath.Join(l.dir, ackFileName))
+	if err != nil {
+		return err
+	}
+
+	l.watermark = watermark
+	l.next = watermark
+
+	l.segments, err = listSegments(l.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, base := range l.segments {
+		if err := l.loadSegment(base); err != nil {
+			return err
+		}
+	}
+
+	if len(l.segments) == 0 || l.activeSize >= segmentMaxBytes {
+		return l.roll()
+	}
+
+	return l.openActive(l.segments[len(l.segments)-1])
+}
+
+func (l *durableLog[T]) loadSegment(base uint64) error {
+	path := segmentPath(l.dir, base)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open segment: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	offset := base
+
+	var size int64
+
+	for {
+		payload, err := readRecord(reader)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, ErrCorruptSegment) {
+			if err := os.Truncate(path, size); err != nil {
+				return fmt.Errorf("truncate torn segment: %w", err)
+			}
+
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if offset >= l.watermark {
+			var data T
+			if err := json.Unmarshal(payload, &data); err != nil {
+				return fmt.Errorf("%w: offset %d: %w", ErrCorruptSegment, offset, err)
+			}
+
+			l.backlog = append(l.backlog, logRecord[T]{offset: offset, data: data})
+		}
+
+		size += int64(recordHeaderSize + len(payload))
+		offset++
+	}
+
+	l.next = max(l.next, offset)
+	l.activeSize = size
+
+	return nil
+}
+
+func (l *durableLog[T]) openActive(base uint64) error {
+	file, err := os.OpenFile(segmentPath(l.dir, base), os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFileMode)
+	if err != nil {
+		return fmt.Errorf("open segment: %w", err)
+	}
+
+	l.active = file
+
+	return nil
+}
+
+func (l *durableLog[T]) roll() error {
+	if err := l.closeFile(); err != nil {
+		return err
+	}
+
+	if err := l.openActive(l.next); err != nil {
+		return err
+	}
+
+	if len(l.segments) == 0 || l.segments[len(l.segments)-1] != l.next {
+		l.segments = append(l.segments, l.next)
+	}
+
+	l.activeSize = 0
+
+	return nil
+}
+
+func (l *durableLog[T]) append(data T) (uint64, int64, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return 0, 0, fmt.Errorf("encode record: %w", err)
+	}
+
+	record := make([]byte, recordHeaderSize, recordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(record, uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:], crc32.ChecksumIEEE(payload))
+	record = append(record, payload...)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.activeSize >= segmentMaxBytes {
+		if err := l.roll(); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if _, err := l.active.Write(record); err != nil {
+		return 0, 0, fmt.Errorf("append record: %w", err)
+	}
+
+	if err := l.active.Sync(); err != nil {
+		return 0, 0, fmt.Errorf("sync segment: %w", err)
+	}
+
+	offset := l.next
+	l.next++
+	l.activeSize += int64(len(record))
+
+	return offset, int64(len(record)), nil
+}
+
+func (l *durableLog[T]) rollback(size int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.active.Truncate(l.activeSize - size); err != nil {
+		return fmt.Errorf("roll back record: %w", err)
+	}
+
+	if err := l.active.Sync(); err != nil {
+		return fmt.Errorf("sync segment: %w", err)
+	}
+
+	l.next--
+	l.activeSize -= size
+
+	return nil
+}
+
+func (l *durableLog[T]) ack(offset uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if offset < l.watermark {
+		return nil
+	}
+
+	l.acked[offset] = struct{}{}
+	previous := l.watermark
+
+	for {
+		if _, ok := l.acked[l.watermark]; !ok {
+			break
+		}
+
+		delete(l.acked, l.watermark)
+		l.watermark++
+	}
+
+	if l.watermark == previous {
+		return nil
+	}
+
+	if err := writeWatermark(filepath.Join(l.dir, ackFileName), l.watermark); err != nil {
+		return err
+	}
+
+	return l.removeAcknowledged()
+}
+
+func (l *durableLog[T]) removeAcknowledged() error {
+	for len(l.segments) > 1 && l.segments[1] <= l.watermark {
+		if err := os.Remove(segmentPath(l.dir, l.segments[0])); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove segment: %w", err)
+		}
+
+		l.segments = l.segments[1:]
+	}
+
+	return nil
+}
+
+func (l *durableLog[T]) takeBacklog() []logRecord[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	backlog := l.backlog
+	l.backlog = nil
+
+	return backlog
+}
+
+func (l *durableLog[T]) closeFile() error {
+	if l.active == nil {
+		return nil
+	}
+
+	err := l.active.Close()
+	l.active = nil
+
+	if err != nil {
+		return fmt.Errorf("close segment: %w", err)
+	}
+
+	return nil
+}
+
+func (l *durableLog[T]) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.closeFile()
+}
+
+func readRecord(reader *bufio.Reader) ([]byte, error) {
+	header := make([]byte, recordHeaderSize)
+
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, fmt.Errorf("read record header: %w", err)
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header))
+
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		return nil, fmt.Errorf("read record: %w", err)
+	}
+
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, ErrCorruptSegment
+	}
+
+	return payload, nil
+}
+
+func listSegments(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("list segments: %w", err)
+	}
+
+	var segments []uint64
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+
+		base, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		segments = append(segments, base)
+	}
+
+	slices.Sort(segments)
+
+	return segments, nil
+}
+
+func segmentPath(dir string, base uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%0*d%s", segmentPrefix, segmentNameWidth, base, segmentSuffix))
+}
+
+func readWatermark(path string) (uint64, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("read ack offset: %w", err)
+	}
+
+	watermark, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse ack offset: %w", err)
+	}
+
+	return watermark, nil
+}
+
+func writeWatermark(path string, watermark uint64) error {
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, []byte(strconv.FormatUint(watermark, 10)), logFileMode); err != nil {
+		return fmt.Errorf("write ack offset: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("commit ack offset: %w", err)
+	}
+
+	return nil
+}
+
+func (ch *channel[T]) sendDurable(ctx context.Context, it item[T]) error {
+	select {
+	case ch.durable.sendTurn <- struct{}{}:
+	case <-ch.sealed:
+		return ErrChanClosed
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrCanceled, ctx.Err())
+	}
+	defer func() { <-ch.durable.sendTurn }()
+
+	offset, size, err := ch.durable.append(it.data)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case ch.ch <- it:
+	case <-ch.sealed:
+		return errors.Join(ErrChanClosed, ch.durable.rollback(size))
+	case <-ctx.Done():
+		return errors.Join(fmt.Errorf("%w: %w", ErrCanceled, ctx.Err()), ch.durable.rollback(size))
+	}
+
+	ch.durable.offsets <- offset
+	ch.sent.Add(1)
+
+	return nil
+}
+
+func (ch *channel[T]) trySendDurable(it item[T]) error {
+	select {
+	case ch.durable.sendTurn <- struct{}{}:
+	default:
+		return ErrChanFull
+	}
+	defer func() { <-ch.durable.sendTurn }()
+
+	if cap(ch.ch) > 0 && len(ch.ch) >= cap(ch.ch) {
+		return ErrChanFull
+	}
+
+	offset, size, err := ch.durable.append(it.data)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case ch.ch <- it:
+	default:
+		return errors.Join(ErrChanFull, ch.durable.rollback(size))
+	}
+
+	ch.durable.offsets <- offset
+	ch.sent.Add(1)
+
+	return nil
+}
+
+func (ch *channel[T]) replay(ctx context.Context, backlog []logRecord[T]) {
+	defer ch.release()
+
+	select {
+	case ch.durable.sendTurn <- struct{}{}:
+	case <-ctx.Done():
+		return
+	}
+	defer func() { <-ch.durable.sendTurn }()
+
+	for _, record := range backlog {
+		select {
+		case ch.ch <- ch.stamp(record.data, time.Time{}):
+		case <-ctx.Done():
+			return
+		}
+
+		ch.durable.offsets <- record.offset
+	}
+}
+
+func (ch *channel[T]) takeDurable(ctx context.Context) (item[T], uint64, error) {
+	var zero item[T]
+
+	select {
+	case ch.durable.recvTurn <- struct{}{}:
+	case <-ctx.Done():
+		return zero, 0, fmt.Errorf("%w: %w", ErrCanceled, ctx.Err())
+	}
+	defer func() { <-ch.durable.recvTurn }()
+
+	for {
+		select {
+		case it, ok := <-ch.ch:
+			if !ok {
+				return zero, 0, ErrChanClosed
+			}
+
+			offset, err := ch.settle(it, <-ch.durable.offsets)
+			if errors.Is(err, errExpired) {
+				continue
+			}
+
+			return it, offset, err
+		case <-ctx.Done():
+			return zero, 0, fmt.Errorf("%w: %w", ErrCanceled, ctx.Err())
+		}
+	}
+}
+
+func (ch *channel[T]) tryTakeDurable() (item[T], uint64, error) {
+	var zero item[T]
+
+	select {
+	case ch.durable.recvTurn <- struct{}{}:
+	default:
+		return zero, 0, ErrChanEmpty
+	}
+	defer func() { <-ch.durable.recvTurn }()
+
+	for {
+		select {
+		case it, ok := <-ch.ch:
+			if !ok {
+				return zero, 0, ErrChanClosed
+			}
+
+			offset, err := ch.settle(it, <-ch.durable.offsets)
+			if errors.Is(err, errExpired) {
+				continue
+			}
+
+			return it, offset, err
+		default:
+			return zero, 0, ErrChanEmpty
+		}
+	}
+}
+
+func (ch *channel[T]) settle(it item[T], offset uint64) (uint64, error) {
+	if !it.expiredAt(time.Now()) {
+		ch.received.Add(1)
+
+		return offset, nil
+	}
+
+	ch.expire(it)
+
+	if err := ch.durable.ack(offset); err != nil {
+		return 0, err
+	}
+
+	return 0, errExpired
+}
+
+func (ch *channel[T]) hasBacklog() bool {
+	if ch.durable == nil {
+		return false
+	}
+
+	ch.durable.mu.Lock()
+	defer ch.durable.mu.Unlock()
+
+	return len(ch.durable.backlog) > 0
+}
+
+func (c *conveyerImpl[T]) startReplays(ctx context.Context, group *errgroup.Group) {
+	for _, channel := range c.chans {
+		if channel.durable == nil {
+			continue
+		}
+
+		backlog := channel.durable.takeBacklog()
+		if len(backlog) == 0 {
+			continue
+		}
+
+		group.Go(func() error {
+			channel.replay(ctx, backlog)
+
+			return nil
+		})
+	}
+}
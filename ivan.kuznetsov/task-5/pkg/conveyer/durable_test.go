@@ -0,0 +1,231 @@
+package conveyer_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	durableChanSize   = 4
+	durableOutSize    = 256
+	crashMessages     = 200
+	crashAfterSent    = 40
+	crashProcessDelay = 2 * time.Millisecond
+	crashRecvTimeout  = 10 * time.Second
+	crashDirEnv       = "CONVEYER_DURABLE_CRASH_DIR"
+	durableSendWait   = 10 * time.Millisecond
+	durablePoll       = time.Millisecond
+)
+
+func newDurableConveyer(
+	t *testing.T,
+	dir string,
+	decorator func(context.Context, chan string, chan string) error,
+) conveyer.Conveyer[string] {
+	t.Helper()
+
+	conv := conveyer.New(durableChanSize)
+	conv.RegisterDecorator(decorator, "in", "out")
+	require.NoError(t, conv.ConfigureChannel("in", conveyer.WithDurable(filepath.Join(dir, "in"))))
+	require.NoError(t, conv.ConfigureChannel("out",
+		conveyer.WithCapacity(durableOutSize), conveyer.WithDurable(filepath.Join(dir, "out"))))
+
+	return conv
+}
+
+func TestDurableChannelReplaysAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	offline := conveyer.New(durableChanSize)
+	require.NoError(t, offline.ConfigureChannel("in", conveyer.WithDurable(filepath.Join(dir, "in"))))
+
+	for _, data := range []string{"a", "b", "c"} {
+		require.NoError(t, offline.Send("in", data))
+	}
+
+	segments, err := filepath.Glob(filepath.Join(dir, "in", "segment-*.log"))
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+
+	torn, err := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = torn.Write([]byte{0, 0, 0, 42, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, torn.Close())
+
+	conv := newDurableConveyer(t, dir, handlers.PrefixDecoratorFunc)
+	done := runConveyer(t, conv)
+
+	for _, expected := range []string{"decorated: a", "decorated: b", "decorated: c"} {
+		data, err := conv.Recv("out")
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	}
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+
+	restarted := newDurableConveyer(t, dir, handlers.PrefixDecoratorFunc)
+	done = runConveyer(t, restarted)
+
+	require.NoError(t, restarted.Send("in", "d"))
+
+	data, err := restarted.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: d", data)
+
+	require.NoError(t, restarted.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestDurableChannelRollsBackFailedSends(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	offline := conveyer.New(durableChanSize)
+	require.NoError(t, offline.ConfigureChannel("in",
+		conveyer.WithCapacity(1), conveyer.WithDurable(filepath.Join(dir, "in"))))
+
+	require.NoError(t, offline.Send("in", "a"))
+	require.ErrorIs(t, offline.TrySend("in", "full"), conveyer.ErrChanFull)
+
+	ctx, cancel := context.WithTimeout(context.Background(), durableSendWait)
+	defer cancel()
+
+	require.ErrorIs(t, offline.SendContext(ctx, "in", "canceled"), conveyer.ErrCanceled)
+
+	conv := newDurableConveyer(t, dir, handlers.PrefixDecoratorFunc)
+	done := runConveyer(t, conv)
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: a", data)
+
+	require.NoError(t, conv.Send("in", "b"))
+
+	data, err = conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: b", data)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+
+	restarted := newDurableConveyer(t, dir, handlers.PrefixDecoratorFunc)
+	done = runConveyer(t, restarted)
+
+	require.NoError(t, restarted.Send("in", "c"))
+
+	data, err = restarted.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: c", data)
+
+	require.NoError(t, restarted.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestDurableUnbufferedChannelAcceptsTrySend(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(durableChanSize)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+	require.NoError(t, conv.ConfigureChannel("in",
+		conveyer.WithCapacity(0), conveyer.WithDurable(filepath.Join(t.TempDir(), "in"))))
+
+	require.ErrorIs(t, conv.TrySend("in", "a"), conveyer.ErrChanFull)
+
+	done := runConveyer(t, conv)
+
+	require.Eventually(t, func() bool {
+		return conv.TrySend("in", "a") == nil
+	}, time.Second, durablePoll)
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: a", data)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestDurableCrashChild(t *testing.T) {
+	t.Parallel()
+
+	dir := os.Getenv(crashDirEnv)
+	if dir == "" {
+		t.Skip("only runs as the crash-simulation child process")
+	}
+
+	conv := newDurableConveyer(t, dir, handlers.GenericDecoratorFunc(func(data string) (string, error) {
+		time.Sleep(crashProcessDelay)
+
+		return "decorated: " + data, nil
+	}))
+
+	go func() {
+		_ = conv.Run(context.Background())
+	}()
+
+	for i := range crashMessages {
+		require.NoError(t, conv.Send("in", strconv.Itoa(i)))
+		fmt.Fprintf(os.Stdout, "sent %d\n", i)
+	}
+
+	time.Sleep(crashRecvTimeout)
+}
+
+func TestDurableChannelsSurviveCrash(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	child := exec.Command(os.Args[0], "-test.run=^TestDurableCrashChild$")
+	child.Env = append(os.Environ(), crashDirEnv+"="+dir)
+
+	stdout, err := child.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, child.Start())
+
+	sent := make(map[string]struct{})
+	scanner := bufio.NewScanner(stdout)
+
+	for len(sent) < crashAfterSent && scanner.Scan() {
+		if id, ok := strings.CutPrefix(scanner.Text(), "sent "); ok {
+			sent["decorated: "+id] = struct{}{}
+		}
+	}
+
+	require.NoError(t, child.Process.Kill())
+	_ = child.Wait()
+	require.Len(t, sent, crashAfterSent)
+
+	conv := newDurableConveyer(t, dir, handlers.PrefixDecoratorFunc)
+	done := runConveyer(t, conv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), crashRecvTimeout)
+	defer cancel()
+
+	for len(sent) > 0 {
+		data, err := conv.RecvContext(ctx, "out")
+		require.NoError(t, err, "messages never delivered: %v", sent)
+
+		delete(sent, data)
+	}
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,241 @@
+package conveyer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrHandlerNotFound = errors.New("handler not found")
+	ErrChanInUse       = errors.New("chan is in use")
+	ErrHandlerRemoved  = errors.New("handler removed")
+)
+
+type execution struct {
+	ctx         context.Context
+	group       *errgroup.Group
+	budget      *restartBudget
+	generations *allForOne
+	workers     map[string]*worker
+	stopping    chan struct{}
+	closed      bool
+}
+
+type worker struct {
+	cancel context.CancelCauseFunc
+	drain  func()
+	done   chan struct{}
+}
+
+func newExecution(ctx context.Context, group *errgroup.Group, config supervisorConfig) *execution {
+	return &execution{
+		ctx:         ctx,
+		group:       group,
+		budget:      newRestartBudget(config),
+		generations: newAllForOne(ctx, 0),
+		workers:     make(map[string]*worker),
+		stopping:    make(chan struct{}),
+		closed:      false,
+	}
+}
+
+func (run *execution) keepAlive() {
+	run.group.Go(func() error {
+		select {
+		case <-run.stopping:
+		case <-run.ctx.Done():
+		}
+
+		return nil
+	})
+}
+
+func (run *execution) stop() {
+	if !run.closed {
+		run.closed = true
+		close(run.stopping)
+	}
+}
+
+func (c *conveyerImpl[T]) running() bool {
+	return c.execution != nil && !c.execution.closed
+}
+
+func (c *conveyerImpl[T]) adopt(id string) {
+	if !c.running() {
+		return
+	}
+
+	c.chans[id].external = true
+	c.chans[id].writers.Store(1)
+}
+
+func (c *conveyerImpl[T]) hold(handl handler[T]) {
+	for id := range uniqueIDs(handl.outputIDs) {
+		channel := c.chans[id]
+		if channel.external || channel.held {
+			continue
+		}
+
+		channel.held = true
+		channel.writers.Add(1)
+	}
+}
+
+func (c *conveyerImpl[T]) attach(handl handler[T]) {
+	for id := range uniqueIDs(handl.outputIDs) {
+		channel := c.chans[id]
+		_, declared := c.inputs[id]
+
+		if channel.external && !declared {
+			channel.external = false
+
+			continue
+		}
+
+		if channel.held {
+			channel.held = false
+
+			continue
+		}
+
+		channel.writers.Add(1)
+	}
+
+	c.launch(handl)
+}
+
+func (c *conveyerImpl[T]) launch(handl handler[T]) {
+	run := c.execution
+	ctx, cancel := context.WithCancelCause(run.ctx)
+
+	handl = c.wrap(handl)
+	opened := c.openPorts(ctx, run.group, handl)
+	handlerCtx := c.withPolicy(ctx, handl)
+
+	budget := run.budget
+	if c.supervisor.strategy == StrategyOneForOne {
+		budget = newRestartBudget(c.supervisor)
+	}
+
+	run.generations.join()
+
+	current := &worker{
+		cancel: cancel,
+		drain:  opened.drain,
+		done:   make(chan struct{}),
+	}
+
+	run.workers[handl.name] = current
+
+	run.group.Go(func() error {
+		defer close(current.done)
+		defer c.retire(run, handl.name, current)
+
+		err := c.supervise(handlerCtx, handl, budget, run.generations, opened.run)
+		err = errors.Join(err, opened.close(err == nil && ctx.Err() == nil))
+		if errors.Is(context.Cause(ctx), ErrHandlerRemoved) {
+			return nil
+		}
+
+		return err
+	})
+}
+
+func (c *conveyerImpl[T]) retire(run *execution, name string, current *worker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if run.workers[name] == current {
+		delete(run.workers, name)
+	}
+}
+
+func (c *conveyerImpl[T]) RemoveHandler(ctx context.Context, name string) error {
+	c.mu.Lock()
+
+	index := slices.IndexFunc(c.handlers, func(handl handler[T]) bool {
+		return handl.name == name
+	})
+	if index < 0 {
+		c.mu.Unlock()
+
+		return fmt.Errorf("%w: %q", ErrHandlerNotFound, name)
+	}
+
+	removed := c.handlers[index]
+	c.handlers = slices.Delete(c.handlers, index, index+1)
+
+	var current *worker
+	if c.execution != nil {
+		current = c.execution.workers[name]
+	}
+
+	if current != nil {
+		c.hold(removed)
+	}
+	c.mu.Unlock()
+
+	if current == nil {
+		return nil
+	}
+
+	current.drain()
+
+	select {
+	case <-current.done:
+		return nil
+	case <-ctx.Done():
+		current.cancel(ErrHandlerRemoved)
+		<-current.done
+
+		return fmt.Errorf("%w: %w", ErrCanceled, ctx.Err())
+	}
+}
+
+func (c *conveyerImpl[T]) RemoveChannel(id string) ([]T, error) {
+	c.mu.Lock()
+
+	channel, ok := c.chans[id]
+	if !ok {
+		c.mu.Unlock()
+
+		return nil, ErrChanNotFound
+	}
+
+	for _, handl := range c.handlers {
+		if slices.Contains(handl.inputIDs, id) || slices.Contains(handl.outputIDs, id) {
+			c.mu.Unlock()
+
+			return nil, fmt.Errorf("%w: %q by %s", ErrChanInUse, id, handl.name)
+		}
+	}
+
+	delete(c.chans, id)
+	delete(c.channelOpts, id)
+	delete(c.inputs, id)
+	delete(c.outputs, id)
+	channel.removed.Store(true)
+	c.mu.Unlock()
+
+	channel.seal()
+
+	var drained []T
+
+	for {
+		data, err := channel.tryRecv()
+		if err != nil {
+			break
+		}
+
+		drained = append(drained, data)
+	}
+
+	channel.close()
+
+	return drained, nil
+}
@@ -0,0 +1,276 @@
+package conveyer_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	dynamicChanSize   = 4
+	dynamicMessages   = 50
+	dynamicRemoveWait = 20 * time.Millisecond
+	dynamicPoll       = time.Millisecond
+)
+
+func stuckDecorator(ctx context.Context, input chan string, output chan string) error {
+	data := <-input
+
+	select {
+	case <-ctx.Done():
+	case output <- data:
+	}
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func tagDecorator(tag string) func(context.Context, chan string, chan string) error {
+	return handlers.GenericDecoratorFunc(func(data string) (string, error) {
+		return tag + ": " + data, nil
+	})
+}
+
+func TestHotSwapDecoratorWithoutLosingMessages(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(dynamicChanSize)
+	conv.RegisterDecorator(tagDecorator("old"), "in", "out", conveyer.WithName("old"))
+
+	require.NoError(t, conv.Start(context.Background()))
+
+	received := make(chan string, dynamicMessages)
+
+	go func() {
+		for data := range conv.All("out") {
+			received <- data
+		}
+
+		close(received)
+	}()
+
+	for i := range dynamicMessages / 2 {
+		require.NoError(t, conv.Send("in", strconv.Itoa(i)))
+	}
+
+	conv.RegisterDecorator(tagDecorator("new"), "in", "out", conveyer.WithName("new"))
+	require.NoError(t, conv.RemoveHandler(context.Background(), "old"))
+	require.ErrorIs(t, conv.RemoveHandler(context.Background(), "old"), conveyer.ErrHandlerNotFound)
+
+	for i := dynamicMessages / 2; i < dynamicMessages; i++ {
+		require.NoError(t, conv.Send("in", strconv.Itoa(i)))
+	}
+
+	require.NoError(t, conv.Stop(context.Background()))
+	require.NoError(t, conv.Wait())
+
+	seen := make(map[string]struct{}, dynamicMessages)
+	tags := make(map[string]int)
+
+	for data := range received {
+		tag, id, ok := strings.Cut(data, ": ")
+		require.True(t, ok)
+
+		seen[id] = struct{}{}
+		tags[tag]++
+	}
+
+	require.Len(t, seen, dynamicMessages)
+	require.Positive(t, tags["new"])
+
+	stats := conv.Stats()
+	require.NotContains(t, stats.Handlers, "old")
+	require.Contains(t, stats.Handlers, "new")
+}
+
+func TestRemoveThenAddKeepsConsumerConnected(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(dynamicChanSize)
+	conv.RegisterDecorator(tagDecorator("old"), "in", "mid", conveyer.WithName("producer"))
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "mid", "out", conveyer.WithName("consumer"))
+
+	done := runConveyer(t, conv)
+
+	roundTripTagged(t, conv, "old")
+	require.NoError(t, conv.RemoveHandler(context.Background(), "producer"))
+
+	conv.RegisterDecorator(tagDecorator("new"), "in", "mid", conveyer.WithName("producer"))
+	roundTripTagged(t, conv, "new")
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+
+	_, err := conv.Recv("out")
+	require.ErrorIs(t, err, conveyer.ErrChanClosed)
+}
+
+func TestRemoveLastHandlerThenAddKeepsRunning(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(dynamicChanSize)
+	conv.RegisterDecorator(tagDecorator("old"), "in", "out", conveyer.WithName("only"))
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.Send("in", "a"))
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "old: a", data)
+
+	require.NoError(t, conv.RemoveHandler(context.Background(), "only"))
+	require.Equal(t, conveyer.StateRunning, conv.State())
+
+	conv.RegisterDecorator(tagDecorator("new"), "in", "out", conveyer.WithName("only"))
+	require.NoError(t, conv.Send("in", "b"))
+
+	data, err = conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "new: b", data)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestRemoveOnlyProducerStillShutsDown(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(dynamicChanSize)
+	conv.RegisterDecorator(tagDecorator("old"), "in", "mid", conveyer.WithName("producer"))
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "mid", "out", conveyer.WithName("consumer"))
+
+	require.NoError(t, conv.Start(context.Background()))
+	require.NoError(t, conv.RemoveHandler(context.Background(), "producer"))
+
+	_, err := conv.TryRecv("mid")
+	require.ErrorIs(t, err, conveyer.ErrChanEmpty)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, conv.Wait())
+}
+
+func roundTripTagged(t *testing.T, conv conveyer.Conveyer[string], tag string) {
+	t.Helper()
+
+	require.NoError(t, conv.Send("in", "a"))
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: "+tag+": a", data)
+}
+
+func TestRemoveChannelDrainsLeftovers(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(dynamicChanSize)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+
+	require.NoError(t, conv.Start(context.Background()))
+
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "side-in", "side-out", conveyer.WithName("side"))
+	require.NoError(t, conv.Send("side-in", "a"))
+
+	data, err := conv.Recv("side-out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: a", data)
+
+	_, err = conv.RemoveChannel("side-in")
+	require.ErrorIs(t, err, conveyer.ErrChanInUse)
+
+	require.NoError(t, conv.RemoveHandler(context.Background(), "side"))
+	require.NoError(t, conv.Send("side-in", "b"))
+
+	leftovers, err := conv.RemoveChannel("side-in")
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, leftovers)
+
+	_, err = conv.RemoveChannel("side-out")
+	require.NoError(t, err)
+
+	require.ErrorIs(t, conv.Send("side-in", "c"), conveyer.ErrChanNotFound)
+
+	roundTrip(t, conv, "d")
+
+	require.NoError(t, conv.Stop(context.Background()))
+	require.NoError(t, conv.Wait())
+}
+
+func TestRemoveHandlerRequeuesMessageHeldByInlet(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(dynamicChanSize)
+	conv.RegisterDecorator(stuckDecorator, "in", "out", conveyer.WithName("stuck"))
+
+	require.NoError(t, conv.Start(context.Background()))
+	require.NoError(t, conv.Send("in", "a"))
+	require.NoError(t, conv.Send("in", "b"))
+
+	require.Eventually(t, func() bool {
+		return conv.Stats().Channels["in"].Received == 2
+	}, time.Second, dynamicPoll)
+
+	ctx, cancel := context.WithTimeout(context.Background(), dynamicRemoveWait)
+	defer cancel()
+
+	require.ErrorIs(t, conv.RemoveHandler(ctx, "stuck"), conveyer.ErrCanceled)
+
+	require.Eventually(t, func() bool {
+		data, err := conv.TryRecv("in")
+
+		return err == nil && data == "b"
+	}, time.Second, dynamicPoll)
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "a", data)
+	require.Zero(t, conv.Stats().Channels["in"].Dropped)
+
+	require.NoError(t, conv.Stop(context.Background()))
+	require.NoError(t, conv.Wait())
+}
+
+func TestRemoveHandlerRequeuesHeldMessageAheadOfFullBuffer(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(dynamicChanSize)
+	conv.RegisterDecorator(stuckDecorator, "in", "out", conveyer.WithName("stuck"))
+	require.NoError(t, conv.ConfigureChannel("in", conveyer.WithCapacity(1)))
+
+	require.NoError(t, conv.Start(context.Background()))
+	require.NoError(t, conv.Send("in", "a"))
+	require.NoError(t, conv.Send("in", "b"))
+
+	require.Eventually(t, func() bool {
+		return conv.Stats().Channels["in"].Received == 2
+	}, time.Second, dynamicPoll)
+
+	require.NoError(t, conv.Send("in", "c"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), dynamicRemoveWait)
+	defer cancel()
+
+	require.ErrorIs(t, conv.RemoveHandler(ctx, "stuck"), conveyer.ErrCanceled)
+
+	require.Eventually(t, func() bool {
+		return conv.Stats().Channels["in"].Depth == 2
+	}, time.Second, dynamicPoll)
+
+	for _, expected := range []string{"b", "c"} {
+		data, err := conv.TryRecv("in")
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	}
+
+	require.Zero(t, conv.Stats().Channels["in"].Dropped)
+
+	require.NoError(t, conv.Stop(context.Background()))
+	require.NoError(t, conv.Wait())
+}
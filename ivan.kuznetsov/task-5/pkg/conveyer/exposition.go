@@ -0,0 +1,210 @@
+package conveyer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	metricsPath         = "/metrics"
+	metricsContentType  = "text/plain; version=0.0.4; charset=utf-8"
+	metricsReadTimeout  = 5 * time.Second
+	metricsShutdownWait = 5 * time.Second
+)
+
+type StatsSource interface {
+	Stats() Stats
+}
+
+type channelMetric struct {
+	name  string
+	kind  string
+	help  string
+	value func(ChannelStats) uint64
+}
+
+type handlerMetric struct {
+	name  string
+	kind  string
+	help  string
+	value func(HandlerStats) uint64
+}
+
+var channelMetrics = []channelMetric{
+	{
+		name:  "conveyer_channel_depth",
+		kind:  "gauge",
+		help:  "Number of messages buffered in the channel.",
+		value: func(s ChannelStats) uint64 { return uint64(s.Depth) },
+	},
+	{
+		name:  "conveyer_channel_capacity",
+		kind:  "gauge",
+		help:  "Buffer capacity of the channel.",
+		value: func(s ChannelStats) uint64 { return uint64(s.Capacity) },
+	},
+	{
+		name:  "conveyer_channel_sent_total",
+		kind:  "counter",
+		help:  "Messages written to the channel.",
+		value: func(s ChannelStats) uint64 { return s.Sent },
+	},
+	{
+		name:  "conveyer_channel_received_total",
+		kind:  "counter",
+		help:  "Messages read from the channel.",
+		value: func(s ChannelStats) uint64 { return s.Received },
+	},
+	{
+		name:  "conveyer_channel_dropped_total",
+		kind:  "counter",
+		help:  "Messages dropped by the overflow policy.",
+		value: func(s ChannelStats) uint64 { return s.Dropped },
+	},
+	{
+		name:  "conveyer_channel_expired_total",
+		kind:  "counter",
+		help:  "Messages discarded after their deadline passed.",
+		value: func(s ChannelStats) uint64 { return s.Expired },
+	},
+}
+
+var handlerMetrics = []handlerMetric{
+	{
+		name:  "conveyer_handler_processed_total",
+		kind:  "counter",
+		help:  "Messages handed to the handler.",
+		value: func(s HandlerStats) uint64 { return s.Processed },
+	},
+	{
+		name:  "conveyer_handler_errors_total",
+		kind:  "counter",
+		help:  "Errors reported by the handler.",
+		value: func(s HandlerStats) uint64 { return s.Errors },
+	},
+}
+
+func WritePrometheus(w io.Writer, stats Stats) error {
+	var out strings.Builder
+
+	channelIDs := slices.Sorted(maps.Keys(stats.Channels))
+	handlerNames := slices.Sorted(maps.Keys(stats.Handlers))
+
+	for _, metric := range channelMetrics {
+		writeHeader(&out, metric.name, metric.kind, metric.help)
+
+		for _, id := range channelIDs {
+			fmt.Fprintf(&out, "%s{channel=%s} %d\n", metric.name, quoteLabel(id), metric.value(stats.Channels[id]))
+		}
+	}
+
+	for _, metric := range handlerMetrics {
+		writeHeader(&out, metric.name, metric.kind, metric.help)
+
+		for _, name := range handlerNames {
+			handlerStats := stats.Handlers[name]
+			fmt.Fprintf(&out, "%s{%s} %d\n", metric.name, handlerLabels(name, handlerStats), metric.value(handlerStats))
+		}
+	}
+
+	writeLatency(&out, stats, handlerNames)
+
+	if _, err := io.WriteString(w, out.String()); err != nil {
+		return fmt.Errorf("write metrics: %w", err)
+	}
+
+	return nil
+}
+
+func writeLatency(out *strings.Builder, stats Stats, handlerNames []string) {
+	const name = "conveyer_handler_latency_seconds"
+
+	writeHeader(out, name, "histogram", "Time the handler spent processing a message.")
+
+	for _, handlerName := range handlerNames {
+		handlerStats := stats.Handlers[handlerName]
+		labels := handlerLabels(handlerName, handlerStats)
+		latency := handlerStats.Latency
+
+		var cumulative uint64
+
+		for i, bound := range latency.Bounds {
+			cumulative += latency.Counts[i]
+			le := strconv.FormatFloat(bound.Seconds(), 'g', -1, 64)
+			fmt.Fprintf(out, "%s_bucket{%s,le=%q} %d\n", name, labels, le, cumulative)
+		}
+
+		fmt.Fprintf(out, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, latency.Count)
+		fmt.Fprintf(out, "%s_sum{%s} %s\n", name, labels, strconv.FormatFloat(latency.Sum.Seconds(), 'g', -1, 64))
+		fmt.Fprintf(out, "%s_count{%s} %d\n", name, labels, latency.Count)
+	}
+}
+
+func writeHeader(out *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(out, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(out, "# TYPE %s %s\n", name, kind)
+}
+
+func handlerLabels(name string, stats HandlerStats) string {
+	return "handler=" + quoteLabel(name) + ",kind=" + quoteLabel(stats.Kind)
+}
+
+func quoteLabel(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	return `"` + replacer.Replace(value) + `"`
+}
+
+func MetricsHandler(source StatsSource) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", metricsContentType)
+
+		_ = WritePrometheus(w, source.Stats())
+	})
+}
+
+func ServeMetrics(ctx context.Context, addr string, source StatsSource) error {
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, MetricsHandler(source))
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: metricsReadTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("serve metrics: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), metricsShutdownWait)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown metrics: %w", err)
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve metrics: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package conveyer
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+type handle[T any] struct {
+	id      string
+	current atomic.Pointer[channel[T]]
+}
+
+type Sender[T any] struct {
+	*handle[T]
+}
+
+type Receiver[T any] struct {
+	*handle[T]
+}
+
+func newHandle[T any](id string, target *channel[T]) *handle[T] {
+	h := &handle[T]{id: id, current: atomic.Pointer[channel[T]]{}}
+	h.current.Store(target)
+
+	return h
+}
+
+func (c *conveyerImpl[T]) Sender(id string) (*Sender[T], error) {
+	target, err := c.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sender[T]{handle: newHandle(id, target)}, nil
+}
+
+func (c *conveyerImpl[T]) Receiver(id string) (*Receiver[T], error) {
+	target, err := c.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Receiver[T]{handle: newHandle(id, target)}, nil
+}
+
+func (h *handle[T]) ID() string {
+	return h.id
+}
+
+func (h *handle[T]) resolve() (*channel[T], error) {
+	current := h.current.Load()
+
+	for {
+		if current.removed.Load() {
+			return nil, ErrChanNotFound
+		}
+
+		next := current.successor.Load()
+		if next == nil {
+			return current, nil
+		}
+
+		h.current.CompareAndSwap(current, next)
+		current = next
+	}
+}
+
+func (s *Sender[T]) Send(data T) error {
+	return s.SendContext(context.Background(), data)
+}
+
+func (s *Sender[T]) SendContext(ctx context.Context, data T) error {
+	channel, err := s.resolve()
+	if err != nil {
+		return err
+	}
+
+	return channel.send(ctx, data)
+}
+
+func (s *Sender[T]) SendTTL(ctx context.Context, data T, ttl time.Duration) error {
+	channel, err := s.resolve()
+	if err != nil {
+		return err
+	}
+
+	return channel.deliver(ctx, data, time.Now().Add(ttl))
+}
+
+func (s *Sender[T]) TrySend(data T) error {
+	channel, err := s.resolve()
+	if err != nil {
+		return err
+	}
+
+	return channel.trySend(data)
+}
+
+func (r *Receiver[T]) Recv() (T, error) {
+	return r.RecvContext(context.Background())
+}
+
+func (r *Receiver[T]) RecvContext(ctx context.Context) (T, error) {
+	channel, err := r.resolve()
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return channel.recv(ctx)
+}
+
+func (r *Receiver[T]) TryRecv() (T, error) {
+	channel, err := r.resolve()
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return channel.tryRecv()
+}
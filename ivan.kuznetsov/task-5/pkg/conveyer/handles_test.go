@@ -0,0 +1,147 @@
+package conveyer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	handleChanSize    = 4
+	benchChanSize     = 1024
+	benchParallelism  = 8
+	handleReconfigure = 2 * handleChanSize
+)
+
+func TestHandlesFollowChannelAcrossReruns(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(handleChanSize)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+
+	_, err := conv.Sender("missing")
+	require.ErrorIs(t, err, conveyer.ErrChanNotFound)
+
+	sender, err := conv.Sender("in")
+	require.NoError(t, err)
+	require.Equal(t, "in", sender.ID())
+
+	receiver, err := conv.Receiver("out")
+	require.NoError(t, err)
+
+	require.NoError(t, sender.Send("queued"))
+	require.NoError(t, conv.ConfigureChannel("in", conveyer.WithCapacity(handleReconfigure)))
+
+	for _, expected := range [][]string{{"decorated: queued", "decorated: a"}, {"decorated: a"}} {
+		require.NoError(t, conv.Start(context.Background()))
+		require.NoError(t, sender.Send("a"))
+
+		for _, want := range expected {
+			data, err := receiver.Recv()
+			require.NoError(t, err)
+			require.Equal(t, want, data)
+		}
+
+		require.NoError(t, conv.Stop(context.Background()))
+		require.NoError(t, conv.Wait())
+
+		_, err = receiver.TryRecv()
+		require.ErrorIs(t, err, conveyer.ErrChanClosed)
+	}
+}
+
+func TestHandlesReportRemovedChannel(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(handleChanSize)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "side-in", "side-out", conveyer.WithName("side"))
+
+	sender, err := conv.Sender("side-in")
+	require.NoError(t, err)
+
+	receiver, err := conv.Receiver("side-in")
+	require.NoError(t, err)
+
+	require.NoError(t, conv.Start(context.Background()))
+	require.NoError(t, conv.RemoveHandler(context.Background(), "side"))
+	require.NoError(t, sender.TrySend("left"))
+
+	leftovers, err := conv.RemoveChannel("side-in")
+	require.NoError(t, err)
+	require.Equal(t, []string{"left"}, leftovers)
+
+	require.ErrorIs(t, sender.Send("late"), conveyer.ErrChanNotFound)
+
+	_, err = receiver.TryRecv()
+	require.ErrorIs(t, err, conveyer.ErrChanNotFound)
+
+	roundTrip(t, conv, "still running")
+
+	require.NoError(t, conv.Stop(context.Background()))
+	require.NoError(t, conv.Wait())
+}
+
+func BenchmarkSendRecv(b *testing.B) {
+	cases := []struct {
+		name  string
+		setup func(b *testing.B, conv conveyer.Conveyer[string]) (func(string) error, func() (string, error))
+	}{
+		{
+			name: "by-id",
+			setup: func(_ *testing.B, conv conveyer.Conveyer[string]) (func(string) error, func() (string, error)) {
+				return func(data string) error { return conv.Send("in", data) },
+					func() (string, error) { return conv.Recv("in") }
+			},
+		},
+		{
+			name: "handle",
+			setup: func(b *testing.B, conv conveyer.Conveyer[string]) (func(string) error, func() (string, error)) {
+				b.Helper()
+
+				sender, err := conv.Sender("in")
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				receiver, err := conv.Receiver("in")
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				return sender.Send, receiver.Recv
+			},
+		},
+	}
+
+	for _, bc := range cases {
+		b.Run(bc.name, func(b *testing.B) {
+			conv := conveyer.New(benchChanSize)
+			conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+
+			send, recv := bc.setup(b, conv)
+
+			b.SetParallelism(benchParallelism)
+			b.ResetTimer()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if err := send("data"); err != nil {
+						b.Error(err)
+
+						return
+					}
+
+					if _, err := recv(); err != nil {
+						b.Error(err)
+
+						return
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package conveyer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+type State int
+
+const (
+	StateCreated State = iota
+	StateRunning
+	StateDraining
+	StateStopped
+	StateFailed
+)
+
+const transitionsSize = 16
+
+type Transition struct {
+	From State
+	To   State
+	Err  error
+}
+
+func (s State) String() string {
+	switch s {
+	case StateCreated:
+		return "created"
+	case StateRunning:
+		return "running"
+	case StateDraining:
+		return "draining"
+	case StateStopped:
+		return "stopped"
+	case StateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+func (c *conveyerImpl[T]) State() State {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.state
+}
+
+func (c *conveyerImpl[T]) Subscribe(ctx context.Context) <-chan Transition {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	subscriber := make(chan Transition, transitionsSize)
+	c.subscribers = append(c.subscribers, subscriber)
+
+	context.AfterFunc(ctx, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.subscribers = slices.DeleteFunc(c.subscribers, func(candidate chan Transition) bool {
+			return candidate == subscriber
+		})
+
+		close(subscriber)
+	})
+
+	return subscriber
+}
+
+func (c *conveyerImpl[T]) DroppedTransitions() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.lagged
+}
+
+func (c *conveyerImpl[T]) Start(ctx context.Context) error {
+	run, err := c.start(ctx)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		_ = run()
+	}()
+
+	return nil
+}
+
+func (c *conveyerImpl[T]) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	if c.state == StateCreated {
+		c.mu.Unlock()
+
+		return ErrNotStarted
+	}
+
+	if c.state == StateRunning {
+		c.transition(StateDraining, nil)
+	}
+
+	if c.execution != nil {
+		c.execution.stop()
+	}
+
+	for _, channel := range c.chans {
+		if channel.external && channel.seal() {
+			channel.release()
+		}
+
+		if channel.held {
+			channel.held = false
+			channel.release()
+		}
+	}
+
+	finished, cancel := c.finished, c.cancel
+	c.mu.Unlock()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		cancel()
+		<-finished
+
+		return fmt.Errorf("shutdown interrupted: %w", ctx.Err())
+	}
+}
+
+func (c *conveyerImpl[T]) Wait() error {
+	c.mu.RLock()
+	finished := c.finished
+	c.mu.RUnlock()
+
+	if finished == nil {
+		return ErrNotStarted
+	}
+
+	<-finished
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.runErr
+}
+
+func (c *conveyerImpl[T]) active() bool {
+	return c.state == StateRunning || c.state == StateDraining
+}
+
+func (c *conveyerImpl[T]) start(ctx context.Context) (func() error, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.active() {
+		return nil, ErrAlreadyStarted
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("invalid topology: %w", err)
+	}
+
+	if c.state != StateCreated {
+		if err := c.renew(); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	finished := make(chan struct{})
+
+	c.finished = finished
+	c.cancel = cancel
+	c.runErr = nil
+	c.countWriters()
+	c.transition(StateRunning, nil)
+
+	group := c.execute(ctx)
+
+	return func() error {
+		defer cancel()
+
+		err := c.await(group)
+		c.finish(finished, err)
+
+		return err
+	}, nil
+}
+
+func (c *conveyerImpl[T]) finish(finished chan struct{}, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.runErr = err
+
+	if err != nil {
+		c.transition(StateFailed, err)
+	} else {
+		c.transition(StateStopped, nil)
+	}
+
+	c.closeEvents()
+	close(finished)
+}
+
+func (c *conveyerImpl[T]) renew() error {
+	for id, previous := range c.expired {
+		c.expired[id] = newChannel[T](cap(previous.ch))
+	}
+
+	for id := range c.chans {
+		options := c.channelOptionsFor(id)
+
+		var durable *durableLog[T]
+
+		if options.durableDir != "" {
+			log, err := openDurableLog[T](options.durableDir, options.capacity)
+			if err != nil {
+				return fmt.Errorf("channel %q: %w", id, err)
+			}
+
+			durable = log
+		}
+
+		previous := c.chans[id]
+
+		c.initChannel(id)
+		c.chans[id].durable = durable
+		previous.successor.Store(c.chans[id])
+	}
+
+	for id := range c.deadLetters {
+		c.deadLetters[id] = newChannel[T](c.size)
+	}
+
+	return nil
+}
+
+func (c *conveyerImpl[T]) transition(to State, err error) {
+	change := Transition{
+		From: c.state,
+		To:   to,
+		Err:  err,
+	}
+
+	c.state = to
+
+	for _, subscriber := range c.subscribers {
+		select {
+		case subscriber <- change:
+		default:
+			c.lagged++
+		}
+	}
+}
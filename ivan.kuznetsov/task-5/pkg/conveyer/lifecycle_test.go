@@ -0,0 +1,128 @@
+package conveyer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	lifecycleChanSize = 4
+	lifecycleReruns   = 10
+)
+
+func roundTrip(t *testing.T, conv conveyer.Conveyer[string], data string) {
+	t.Helper()
+
+	require.NoError(t, conv.Send("in", data))
+
+	received, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: "+data, received)
+}
+
+func TestLifecycleStartStopAndRestart(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conv := conveyer.New(lifecycleChanSize)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+	transitions := conv.Subscribe(ctx)
+
+	require.Equal(t, conveyer.StateCreated, conv.State())
+	require.ErrorIs(t, conv.Stop(context.Background()), conveyer.ErrNotStarted)
+	require.ErrorIs(t, conv.Wait(), conveyer.ErrNotStarted)
+
+	require.NoError(t, conv.Start(context.Background()))
+	require.Equal(t, conveyer.StateRunning, conv.State())
+	require.ErrorIs(t, conv.Start(context.Background()), conveyer.ErrAlreadyStarted)
+
+	roundTrip(t, conv, "a")
+
+	require.NoError(t, conv.Stop(context.Background()))
+	require.NoError(t, conv.Wait())
+	require.Equal(t, conveyer.StateStopped, conv.State())
+	require.ErrorIs(t, conv.Send("in", "lost"), conveyer.ErrChanClosed)
+
+	require.NoError(t, conv.Start(context.Background()))
+	roundTrip(t, conv, "b")
+	require.NoError(t, conv.Stop(context.Background()))
+	require.NoError(t, conv.Wait())
+
+	expected := []conveyer.State{
+		conveyer.StateRunning, conveyer.StateDraining, conveyer.StateStopped,
+		conveyer.StateRunning, conveyer.StateDraining, conveyer.StateStopped,
+	}
+
+	from := conveyer.StateCreated
+
+	for _, state := range expected {
+		transition := <-transitions
+		require.Equal(t, from, transition.From)
+		require.Equal(t, state, transition.To)
+
+		from = state
+	}
+
+	cancel()
+
+	_, ok := <-transitions
+	require.False(t, ok)
+}
+
+func TestLifecycleReportsFailure(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(lifecycleChanSize)
+	conv.RegisterDecorator(handlers.GenericDecoratorFunc(flakyDecorator), "in", "out")
+	transitions := conv.Subscribe(context.Background())
+
+	require.NoError(t, conv.Start(context.Background()))
+	require.NoError(t, conv.Send("in", "bad"))
+
+	err := conv.Wait()
+	require.ErrorIs(t, err, errFlaky)
+	require.Equal(t, conveyer.StateFailed, conv.State())
+
+	require.Equal(t, conveyer.StateRunning, (<-transitions).To)
+
+	failed := <-transitions
+	require.Equal(t, conveyer.StateFailed, failed.To)
+	require.ErrorIs(t, failed.Err, errFlaky)
+
+	require.NoError(t, conv.Start(context.Background()))
+	roundTrip(t, conv, "a")
+	require.NoError(t, conv.Stop(context.Background()))
+	require.NoError(t, conv.Wait())
+}
+
+func TestLaggingSubscriberCountsDroppedTransitions(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(lifecycleChanSize)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+
+	lagging := conv.Subscribe(context.Background())
+	steady := conv.Subscribe(context.Background())
+	delivered := 0
+
+	for range lifecycleReruns {
+		require.NoError(t, conv.Start(context.Background()))
+		require.NoError(t, conv.Stop(context.Background()))
+		require.NoError(t, conv.Wait())
+
+		for len(steady) > 0 {
+			<-steady
+			delivered++
+		}
+	}
+
+	require.Len(t, lagging, cap(lagging))
+	require.Positive(t, conv.DroppedTransitions())
+	require.Equal(t, uint64(delivered), uint64(len(lagging))+conv.DroppedTransitions())
+}
@@ -0,0 +1,166 @@
+package conveyer
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var latencyBuckets = []time.Duration{
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+type Stats struct {
+	Channels map[string]ChannelStats
+	Handlers map[string]HandlerStats
+}
+
+type ChannelStats struct {
+	Depth    int
+	Capacity int
+	Sent     uint64
+	Received uint64
+	Dropped  uint64
+	Expired  uint64
+}
+
+type HandlerStats struct {
+	Kind      string
+	Processed uint64
+	Errors    uint64
+	Latency   LatencyHistogram
+}
+
+type LatencyHistogram struct {
+	Bounds []time.Duration
+	Counts []uint64
+	Count  uint64
+	Sum    time.Duration
+}
+
+type handlerStats struct {
+	processed   atomic.Uint64
+	errors      atomic.Uint64
+	lastHandoff atomic.Int64
+	applied     atomic.Bool
+
+	mu      sync.Mutex
+	buckets []uint64
+	count   uint64
+	sum     time.Duration
+}
+
+func newHandlerStats() *handlerStats {
+	return &handlerStats{
+		processed:   atomic.Uint64{},
+		errors:      atomic.Uint64{},
+		lastHandoff: atomic.Int64{},
+		applied:     atomic.Bool{},
+		mu:          sync.Mutex{},
+		buckets:     make([]uint64, len(latencyBuckets)+1),
+		count:       0,
+		sum:         0,
+	}
+}
+
+func (s *handlerStats) handedOff(now time.Time) {
+	s.lastHandoff.Store(now.UnixNano())
+	s.processed.Add(1)
+}
+
+func (s *handlerStats) measure(started time.Time) {
+	s.applied.Store(true)
+	s.observe(time.Since(started))
+}
+
+func (s *handlerStats) emitted(now time.Time) {
+	if s.applied.Load() {
+		return
+	}
+
+	started := s.lastHandoff.Swap(0)
+	if started == 0 {
+		return
+	}
+
+	s.observe(now.Sub(time.Unix(0, started)))
+}
+
+func (s *handlerStats) observe(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket := len(latencyBuckets)
+
+	for i, bound := range latencyBuckets {
+		if latency <= bound {
+			bucket = i
+
+			break
+		}
+	}
+
+	s.buckets[bucket]++
+	s.count++
+	s.sum += latency
+}
+
+func (s *handlerStats) snapshot(kind handlerType) HandlerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return HandlerStats{
+		Kind:      kind.String(),
+		Processed: s.processed.Load(),
+		Errors:    s.errors.Load(),
+		Latency: LatencyHistogram{
+			Bounds: append([]time.Duration{}, latencyBuckets...),
+			Counts: append([]uint64{}, s.buckets...),
+			Count:  s.count,
+			Sum:    s.sum,
+		},
+	}
+}
+
+func (ch *channel[T]) snapshot() ChannelStats {
+	ch.returnMu.Lock()
+	returned := len(ch.returned)
+	ch.returnMu.Unlock()
+
+	return ChannelStats{
+		Depth:    len(ch.ch) + returned,
+		Capacity: cap(ch.ch),
+		Sent:     ch.sent.Load(),
+		Received: ch.received.Load(),
+		Dropped:  ch.dropped.Load(),
+		Expired:  ch.expired.Load(),
+	}
+}
+
+func (c *conveyerImpl[T]) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := Stats{
+		Channels: make(map[string]ChannelStats, len(c.chans)),
+		Handlers: make(map[string]HandlerStats, len(c.handlers)),
+	}
+
+	for id, channel := range c.chans {
+		stats.Channels[id] = channel.snapshot()
+	}
+
+	for _, handl := range c.handlers {
+		stats.Handlers[handl.name] = handl.stats.snapshot(handl.kind)
+	}
+
+	return stats
+}
@@ -0,0 +1,93 @@
+package conveyer_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const metricsChanSize = 4
+
+func runMetricsConveyer(t *testing.T) conveyer.Conveyer[string] {
+	t.Helper()
+
+	conv := conveyer.New(metricsChanSize)
+	conv.RegisterDecorator(
+		handlers.PrefixDecoratorFunc, "in", "out",
+		conveyer.WithName("prefix"), conveyer.WithSkipOnError(),
+	)
+
+	done := runConveyer(t, conv)
+
+	for _, data := range []string{"a", "no decorator", "b"} {
+		require.NoError(t, conv.Send("in", data))
+	}
+
+	for range 2 {
+		_, err := conv.Recv("out")
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+
+	return conv
+}
+
+func TestStatsCountsMessagesAndErrors(t *testing.T) {
+	t.Parallel()
+
+	stats := runMetricsConveyer(t).Stats()
+
+	require.Equal(t, conveyer.ChannelStats{
+		Depth:    0,
+		Capacity: metricsChanSize,
+		Sent:     3,
+		Received: 3,
+		Dropped:  0,
+		Expired:  0,
+	}, stats.Channels["in"])
+	require.Equal(t, uint64(2), stats.Channels["out"].Sent)
+	require.Equal(t, uint64(2), stats.Channels["out"].Received)
+
+	prefix := stats.Handlers["prefix"]
+	require.Equal(t, "decorator", prefix.Kind)
+	require.Equal(t, uint64(3), prefix.Processed)
+	require.Equal(t, uint64(1), prefix.Errors)
+	require.Equal(t, uint64(3), prefix.Latency.Count)
+	require.Len(t, prefix.Latency.Counts, len(prefix.Latency.Bounds)+1)
+}
+
+func TestMetricsHandlerServesPrometheusText(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(conveyer.MetricsHandler(runMetricsConveyer(t)))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+
+	text := string(body)
+	require.Contains(t, text, "# TYPE conveyer_channel_sent_total counter\n")
+	require.Contains(t, text, "conveyer_channel_sent_total{channel=\"in\"} 3\n")
+	require.Contains(t, text, "conveyer_channel_capacity{channel=\"out\"} 4\n")
+	require.Contains(t, text, "conveyer_handler_errors_total{handler=\"prefix\",kind=\"decorator\"} 1\n")
+	require.Contains(t, text, "# TYPE conveyer_handler_latency_seconds histogram\n")
+	require.Contains(t, text, "conveyer_handler_latency_seconds_bucket{handler=\"prefix\",kind=\"decorator\",le=\"+Inf\"} 3\n")
+	require.Contains(t, text, "conveyer_handler_latency_seconds_count{handler=\"prefix\",kind=\"decorator\"} 3\n")
+}
@@ -0,0 +1,139 @@
+package conveyer
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/errpolicy"
+)
+
+var ErrHandlerPanic = errpolicy.ErrPanic
+
+type HandlerInfo struct {
+	Name    string
+	Kind    string
+	Inputs  []string
+	Outputs []string
+}
+
+type HandlerFunc func(ctx context.Context) error
+
+type Middleware func(info HandlerInfo, next HandlerFunc) HandlerFunc
+
+func (c *conveyerImpl[T]) Use(middlewares ...Middleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+// Recover converts handler panics into ErrHandlerPanic errors. Goroutines started by a handler are
+// covered only when they run through errpolicy.Guard with the handler context.
+func Recover() Middleware {
+	return func(info HandlerInfo, next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context) error {
+			ctx = errpolicy.WithRecover(ctx, info.Name)
+
+			return errpolicy.Guard(ctx, func() error {
+				return next(ctx)
+			})
+		}
+	}
+}
+
+func Logging(logger *slog.Logger) Middleware {
+	return func(info HandlerInfo, next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context) error {
+			attrs := []any{slog.String("handler", info.Name), slog.String("kind", info.Kind)}
+			started := time.Now()
+
+			logger.InfoContext(ctx, "handler started", attrs...)
+
+			err := next(ctx)
+
+			attrs = append(attrs, slog.Duration("elapsed", time.Since(started)))
+			if err != nil {
+				logger.ErrorContext(ctx, "handler failed", append(attrs, slog.Any("error", err))...)
+
+				return err
+			}
+
+			logger.InfoContext(ctx, "handler stopped", attrs...)
+
+			return nil
+		}
+	}
+}
+
+func RunDuration(record func(info HandlerInfo, elapsed time.Duration)) Middleware {
+	return func(info HandlerInfo, next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context) error {
+			started := time.Now()
+			err := next(ctx)
+
+			record(info, time.Since(started))
+
+			return err
+		}
+	}
+}
+
+func (h handler[T]) info() HandlerInfo {
+	return HandlerInfo{
+		Name:    h.name,
+		Kind:    h.kind.String(),
+		Inputs:  append([]string{}, h.inputIDs...),
+		Outputs: append([]string{}, h.outputIDs...),
+	}
+}
+
+func chain(info HandlerInfo, middlewares []Middleware, fn HandlerFunc) HandlerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		fn = middlewares[i](info, fn)
+	}
+
+	return fn
+}
+
+func (c *conveyerImpl[T]) wrap(handl handler[T]) handler[T] {
+	if len(c.middlewares) == 0 {
+		return handl
+	}
+
+	info := handl.info()
+	middlewares := append([]Middleware{}, c.middlewares...)
+
+	switch handl.kind {
+	case hDecorator:
+		fn := handl.fnDecorator
+		handl.fnDecorator = func(ctx context.Context, input chan T, output chan T) error {
+			return chain(info, middlewares, func(ctx context.Context) error {
+				return fn(ctx, input, output)
+			})(ctx)
+		}
+	case hMultiplexer:
+		fn := handl.fnMultiplexer
+		handl.fnMultiplexer = func(ctx context.Context, inputs []chan T, output chan T) error {
+			return chain(info, middlewares, func(ctx context.Context) error {
+				return fn(ctx, inputs, output)
+			})(ctx)
+		}
+	case hSeparator:
+		fn := handl.fnSeparator
+		handl.fnSeparator = func(ctx context.Context, input chan T, outputs []chan T) error {
+			return chain(info, middlewares, func(ctx context.Context) error {
+				return fn(ctx, input, outputs)
+			})(ctx)
+		}
+	case hStage:
+		fn := handl.fnStage
+		handl.fnStage = func(ctx context.Context, inputs []chan T, outputs []chan T) error {
+			return chain(info, middlewares, func(ctx context.Context) error {
+				return fn(ctx, inputs, outputs)
+			})(ctx)
+		}
+	}
+
+	return handl
+}
@@ -0,0 +1,145 @@
+package conveyer_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const middlewareChanSize = 4
+
+func panickyDecorator(data string) (string, error) {
+	if data == "boom" {
+		panic("exploded on " + data)
+	}
+
+	return data, nil
+}
+
+func TestRecoverTurnsPanicIntoError(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(middlewareChanSize)
+	conv.Use(conveyer.Recover())
+	conv.RegisterDecorator(handlers.GenericDecoratorFunc(panickyDecorator), "in", "out",
+		conveyer.WithName("panicky"), conveyer.WithReplicas(2))
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.Send("in", "boom"))
+
+	err := <-done
+	require.ErrorIs(t, err, conveyer.ErrHandlerPanic)
+	require.ErrorContains(t, err, "panicky: exploded on boom")
+	require.ErrorContains(t, err, "middleware_test.go")
+}
+
+func TestRecoverCoversGoroutinesStartedByHandlers(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(middlewareChanSize)
+	conv.Use(conveyer.Recover())
+	conv.RegisterMultiplexer(handlers.GenericMultiplexerFunc(func(data string) bool {
+		if data == "boom" {
+			panic("skip exploded on " + data)
+		}
+
+		return false
+	}), []string{"a", "b"}, "out", conveyer.WithName("merge"))
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.Send("b", "boom"))
+
+	err := <-done
+	require.ErrorIs(t, err, conveyer.ErrHandlerPanic)
+	require.ErrorContains(t, err, "merge: skip exploded on boom")
+}
+
+func TestLoggingAndRunDurationMiddlewares(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logs    bytes.Buffer
+		mu      sync.Mutex
+		elapsed = make(map[string]time.Duration)
+	)
+
+	conv := conveyer.New(middlewareChanSize)
+	conv.Use(
+		conveyer.Logging(slog.New(slog.NewTextHandler(&logs, nil))),
+		conveyer.RunDuration(func(info conveyer.HandlerInfo, took time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			elapsed[info.Name] = took
+		}),
+	)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out", conveyer.WithName("prefix"))
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.Send("in", "a"))
+
+	_, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+
+	require.Contains(t, logs.String(), `msg="handler started" handler=prefix kind=decorator`)
+	require.Contains(t, logs.String(), `msg="handler stopped" handler=prefix kind=decorator elapsed=`)
+	require.Contains(t, elapsed, "prefix")
+}
+
+func TestCustomMiddlewaresWrapInOrder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+
+	record := func(name string) conveyer.Middleware {
+		return func(info conveyer.HandlerInfo, next conveyer.HandlerFunc) conveyer.HandlerFunc {
+			return func(ctx context.Context) error {
+				mu.Lock()
+				calls = append(calls, name+" enter "+info.Kind)
+				mu.Unlock()
+
+				err := next(ctx)
+
+				mu.Lock()
+				calls = append(calls, name+" exit "+info.Kind)
+				mu.Unlock()
+
+				return err
+			}
+		}
+	}
+
+	conv := conveyer.New(middlewareChanSize)
+	conv.Use(record("outer"), record("inner"))
+	conv.RegisterSeparator(handlers.SeparatorFunc, "in", []string{"out"})
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.Send("in", "a"))
+
+	_, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+	require.Equal(t, []string{
+		"outer enter separator",
+		"inner enter separator",
+		"inner exit separator",
+		"outer exit separator",
+	}, calls)
+}
@@ -11,23 +11,43 @@ const (
 	hDecorator handlerType = iota
 	hMultiplexer
 	hSeparator
+	hStage
 )
 
-type handler struct {
+type handler[T any] struct {
 	kind handlerType
 
-	fnDecorator   func(context.Context, chan string, chan string) error
-	fnMultiplexer func(context.Context, []chan string, chan string) error
-	fnSeparator   func(context.Context, chan string, []chan string) error
+	fnDecorator   func(context.Context, chan T, chan T) error
+	fnMultiplexer func(context.Context, []chan T, chan T) error
+	fnSeparator   func(context.Context, chan T, []chan T) error
+	fnStage       func(context.Context, []chan T, []chan T) error
 
 	inputIDs  []string
 	outputIDs []string
+
+	options handlerOptions
+	name    string
+	stats   *handlerStats
 }
 
-type conveyerImpl struct {
-	size     int
-	mu       sync.RWMutex
-	chans    map[string]chan string
-	handlers []handler
-	started  bool
+type conveyerImpl[T any] struct {
+	size        int
+	mu          sync.RWMutex
+	chans       map[string]*channel[T]
+	channelOpts map[string]channelOptions
+	deadLetters map[string]*channel[T]
+	expired     map[string]*channel[T]
+	handlers    []handler[T]
+	inputs      map[string]struct{}
+	outputs     map[string]struct{}
+	state       State
+	finished    chan struct{}
+	cancel      context.CancelFunc
+	runErr      error
+	subscribers []chan Transition
+	lagged      uint64
+	execution   *execution
+	middlewares []Middleware
+	supervisor  supervisorConfig
+	events      chan SupervisorEvent
 }
@@ -0,0 +1,207 @@
+package conveyer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidCapacity = errors.New("channel capacity must not be negative")
+
+type OverflowPolicy int
+
+const (
+	OverflowBlock OverflowPolicy = iota
+	OverflowDropNewest
+	OverflowDropOldest
+	OverflowError
+)
+
+type ChannelOption func(*channelOptions)
+
+type channelOptions struct {
+	capacity   int
+	overflow   OverflowPolicy
+	durableDir string
+	ttl        time.Duration
+	expired    string
+	priority   int
+}
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropNewest:
+		return "drop-newest"
+	case OverflowDropOldest:
+		return "drop-oldest"
+	case OverflowError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+func WithCapacity(capacity int) ChannelOption {
+	return func(opts *channelOptions) {
+		opts.capacity = capacity
+	}
+}
+
+func WithOverflow(policy OverflowPolicy) ChannelOption {
+	return func(opts *channelOptions) {
+		opts.overflow = policy
+	}
+}
+
+func (c *conveyerImpl[T]) ConfigureChannel(id string, opts ...ChannelOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous, exists := c.chans[id]
+	if exists && c.active() {
+		return ErrAlreadyStarted
+	}
+
+	options := c.channelOptionsFor(id)
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.capacity < 0 {
+		return fmt.Errorf("channel %q: %w: got %d", id, ErrInvalidCapacity, options.capacity)
+	}
+
+	if options.durableDir != "" && options.overflow != OverflowBlock {
+		return fmt.Errorf("channel %q: %w", id, ErrDurableOverflow)
+	}
+
+	var durable *durableLog[T]
+
+	if options.durableDir != "" {
+		log, err := openDurableLog[T](options.durableDir, options.capacity)
+		if err != nil {
+			return fmt.Errorf("channel %q: %w", id, err)
+		}
+
+		durable = log
+	}
+
+	c.channelOpts[id] = options
+
+	c.initChannel(id)
+	c.chans[id].durable = durable
+	c.adopt(id)
+
+	if exists {
+		c.migrate(previous, c.chans[id])
+		previous.successor.Store(c.chans[id])
+	}
+
+	return nil
+}
+
+func (c *conveyerImpl[T]) migrate(previous *channel[T], next *channel[T]) {
+	if previous.durable != nil {
+		_ = previous.durable.close()
+
+		if next.durable != nil {
+			return
+		}
+	}
+
+	previous.returnMu.Lock()
+	returned := previous.returned
+	previous.returned = nil
+	previous.returnMu.Unlock()
+
+	for _, it := range returned {
+		next.pushFront(it)
+	}
+
+	for len(previous.ch) > 0 {
+		it := <-previous.ch
+
+		if next.durable != nil {
+			_ = next.trySend(it.data)
+
+			continue
+		}
+
+		_ = next.offer(it)
+	}
+}
+
+func (c *conveyerImpl[T]) Dropped(id string) (uint64, error) {
+	channel, err := c.lookup(id)
+	if err != nil {
+		return 0, err
+	}
+
+	return channel.dropped.Load(), nil
+}
+
+func (c *conveyerImpl[T]) channelOptionsFor(id string) channelOptions {
+	if options, ok := c.channelOpts[id]; ok {
+		return options
+	}
+
+	return channelOptions{
+		capacity:   c.size,
+		overflow:   OverflowBlock,
+		durableDir: "",
+		ttl:        0,
+		expired:    "",
+		priority:   0,
+	}
+}
+
+func (ch *channel[T]) offer(it item[T]) error {
+	select {
+	case ch.ch <- it:
+		ch.sent.Add(1)
+
+		return nil
+	default:
+	}
+
+	switch ch.overflow {
+	case OverflowDropNewest:
+		ch.dropped.Add(1)
+
+		return nil
+	case OverflowDropOldest:
+		return ch.evictOldest(it)
+	default:
+		return ErrChanFull
+	}
+}
+
+func (ch *channel[T]) evictOldest(it item[T]) error {
+	ch.dropMu.Lock()
+	defer ch.dropMu.Unlock()
+
+	if cap(ch.ch) == 0 {
+		ch.dropped.Add(1)
+
+		return nil
+	}
+
+	for {
+		select {
+		case ch.ch <- it:
+			ch.sent.Add(1)
+
+			return nil
+		default:
+		}
+
+		select {
+		case <-ch.ch:
+			ch.dropped.Add(1)
+		default:
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package conveyer_test
+
+import (
+	"testing"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const overflowCapacity = 2
+
+func pending(conv conveyer.Conveyer[string], id string) []string {
+	var values []string
+
+	for {
+		data, err := conv.TryRecv(id)
+		if err != nil {
+			return values
+		}
+
+		values = append(values, data)
+	}
+}
+
+func TestOverflowPolicies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		policy  conveyer.OverflowPolicy
+		err     error
+		kept    []string
+		dropped uint64
+	}{
+		{policy: conveyer.OverflowDropNewest, err: nil, kept: []string{"a", "b"}, dropped: 1},
+		{policy: conveyer.OverflowDropOldest, err: nil, kept: []string{"b", "c"}, dropped: 1},
+		{policy: conveyer.OverflowError, err: conveyer.ErrChanFull, kept: []string{"a", "b"}, dropped: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.policy.String(), func(t *testing.T) {
+			t.Parallel()
+
+			conv := conveyer.New(1)
+			conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+			require.NoError(t, conv.ConfigureChannel("in",
+				conveyer.WithCapacity(overflowCapacity), conveyer.WithOverflow(test.policy)))
+
+			require.NoError(t, conv.Send("in", "a"))
+			require.NoError(t, conv.Send("in", "b"))
+
+			err := conv.Send("in", "c")
+			if test.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, test.err)
+			}
+
+			dropped, err := conv.Dropped("in")
+			require.NoError(t, err)
+			require.Equal(t, test.dropped, dropped)
+			require.Equal(t, test.kept, pending(conv, "in"))
+		})
+	}
+}
+
+func TestBlockingChannelReportsFullOnTrySend(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(1)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+	require.NoError(t, conv.ConfigureChannel("in", conveyer.WithCapacity(overflowCapacity)))
+
+	require.NoError(t, conv.TrySend("in", "a"))
+	require.NoError(t, conv.TrySend("in", "b"))
+	require.ErrorIs(t, conv.TrySend("in", "c"), conveyer.ErrChanFull)
+
+	dropped, err := conv.Dropped("in")
+	require.NoError(t, err)
+	require.Zero(t, dropped)
+
+	_, err = conv.Dropped("missing")
+	require.ErrorIs(t, err, conveyer.ErrChanNotFound)
+}
+
+func TestConfigureChannelRejectsNegativeCapacity(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(1)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+
+	err := conv.ConfigureChannel("in", conveyer.WithCapacity(-1))
+	require.ErrorIs(t, err, conveyer.ErrInvalidCapacity)
+
+	require.NoError(t, conv.TrySend("in", "a"))
+}
@@ -0,0 +1,265 @@
+package conveyer
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/errpolicy"
+)
+
+const maxRetryBackoff = time.Minute
+
+type errorAction int
+
+const (
+	actionFailFast errorAction = iota
+	actionSkip
+	actionDeadLetter
+)
+
+type HandlerOption func(*handlerOptions)
+
+type handlerOptions struct {
+	name       string
+	onError    errorAction
+	deadLetter string
+	retries    int
+	backoff    time.Duration
+	replicas   int
+	ordered    bool
+}
+
+type DeadLetter[T any] struct {
+	Data    T
+	Err     error
+	Handler string
+}
+
+type failure struct {
+	err     error
+	handler string
+}
+
+type messagePolicy struct {
+	options    handlerOptions
+	deadLetter func(ctx context.Context, data any, err error) error
+	dropped    func()
+	stats      *handlerStats
+	lane       *lane
+	name       string
+	priorities []int
+}
+
+func WithName(name string) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.name = name
+	}
+}
+
+func WithFailFast() HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.onError = actionFailFast
+	}
+}
+
+func WithSkipOnError() HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.onError = actionSkip
+	}
+}
+
+func WithDeadLetter(id string) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.onError = actionDeadLetter
+		opts.deadLetter = id
+	}
+}
+
+func WithRetry(retries int, backoff time.Duration) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.retries = retries
+		opts.backoff = backoff
+	}
+}
+
+func newHandlerOptions(opts []HandlerOption) handlerOptions {
+	options := handlerOptions{
+		name:       "",
+		onError:    actionFailFast,
+		deadLetter: "",
+		retries:    0,
+		backoff:    0,
+		replicas:   1,
+		ordered:    false,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+func Apply[T any](ctx context.Context, data T, fn func(T) (T, error)) (T, bool, error) {
+	return errpolicy.Apply(ctx, data, fn)
+}
+
+func ReportError[T any](ctx context.Context, data T, err error) error {
+	return errpolicy.Report(ctx, data, err)
+}
+
+func retryDelay(backoff time.Duration, attempt int) time.Duration {
+	delay := backoff
+
+	for range attempt {
+		if delay > maxRetryBackoff/2 {
+			return max(backoff, maxRetryBackoff)
+		}
+
+		delay *= 2
+	}
+
+	return delay
+}
+
+func policyFrom(ctx context.Context) (*messagePolicy, bool) {
+	handlerPolicy, ok := errpolicy.From(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	policy, ok := handlerPolicy.(*messagePolicy)
+
+	return policy, ok
+}
+
+func (p *messagePolicy) Backoff(attempt int) (time.Duration, bool) {
+	if attempt >= p.options.retries {
+		return 0, false
+	}
+
+	return retryDelay(p.options.backoff, attempt), true
+}
+
+func (p *messagePolicy) Measure(started time.Time) {
+	p.stats.measure(started)
+}
+
+func (p *messagePolicy) Fail(ctx context.Context, data any, err error) error {
+	switch p.options.onError {
+	case actionSkip:
+		p.stats.errors.Add(1)
+		p.notifyDropped()
+
+		return nil
+	case actionDeadLetter:
+		if err := p.deadLetter(ctx, data, err); err != nil {
+			return err
+		}
+
+		p.stats.errors.Add(1)
+		p.notifyDropped()
+
+		return nil
+	default:
+		return err
+	}
+}
+
+func HandlerName(ctx context.Context) string {
+	policy, ok := policyFrom(ctx)
+	if !ok {
+		return ""
+	}
+
+	return policy.name
+}
+
+func (p *messagePolicy) notifyDropped() {
+	if p.dropped != nil {
+		p.dropped()
+	}
+}
+
+func withDropHook(ctx context.Context, hook func()) context.Context {
+	policy, ok := policyFrom(ctx)
+	if !ok {
+		return ctx
+	}
+
+	hooked := *policy
+	hooked.dropped = hook
+
+	return errpolicy.With(ctx, &hooked)
+}
+
+func withLane(ctx context.Context, current *lane) context.Context {
+	policy, ok := policyFrom(ctx)
+	if !ok {
+		return ctx
+	}
+
+	laned := *policy
+	laned.lane = current
+
+	return errpolicy.With(ctx, &laned)
+}
+
+func (c *conveyerImpl[T]) withPolicy(ctx context.Context, handl handler[T]) context.Context {
+	deadLetters := c.deadLetters[handl.options.deadLetter]
+	name := handl.name
+
+	return errpolicy.With(ctx, &messagePolicy{
+		options: handl.options,
+		deadLetter: func(ctx context.Context, data any, err error) error {
+			value, _ := data.(T)
+
+			return deadLetters.enqueue(ctx, item[T]{
+				data:     value,
+				deadline: time.Time{},
+				failure:  &failure{err: err, handler: name},
+			})
+		},
+		dropped:    nil,
+		stats:      handl.stats,
+		lane:       nil,
+		name:       name,
+		priorities: c.priorities(handl.inputIDs),
+	})
+}
+
+func (c *conveyerImpl[T]) ensureDeadLetter(options handlerOptions) {
+	if options.onError != actionDeadLetter {
+		return
+	}
+
+	if _, ok := c.deadLetters[options.deadLetter]; !ok {
+		c.deadLetters[options.deadLetter] = newChannel[T](c.size)
+	}
+}
+
+func (c *conveyerImpl[T]) RecvDeadLetter(id string) (DeadLetter[T], error) {
+	c.mu.RLock()
+	channel, okey := c.deadLetters[id]
+	c.mu.RUnlock()
+
+	if !okey {
+		var zero DeadLetter[T]
+
+		return zero, ErrChanNotFound
+	}
+
+	it, _, err := channel.take(context.Background())
+	if err != nil {
+		var zero DeadLetter[T]
+
+		return zero, err
+	}
+
+	letter := DeadLetter[T]{Data: it.data, Err: nil, Handler: ""}
+	if it.failure != nil {
+		letter.Err, letter.Handler = it.failure.err, it.failure.handler
+	}
+
+	return letter, nil
+}
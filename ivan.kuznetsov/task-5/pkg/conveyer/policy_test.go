@@ -0,0 +1,156 @@
+package conveyer_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	policyChanSize = 4
+	policyBackoff  = time.Millisecond
+	policyRetries  = 2
+	policyPoll     = time.Millisecond
+)
+
+var errFlaky = errors.New("flaky failure")
+
+func flakyDecorator(data string) (string, error) {
+	if data == "bad" {
+		return "", errFlaky
+	}
+
+	return "decorated: " + data, nil
+}
+
+func runConveyer(t testing.TB, conv conveyer.Conveyer[string]) chan error {
+	t.Helper()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conv.Run(context.Background())
+	}()
+
+	return done
+}
+
+func failingDecorator(attempts *atomic.Int32, failures int32) func(string) (string, error) {
+	return func(data string) (string, error) {
+		if attempts.Add(1) <= failures {
+			return "", errFlaky
+		}
+
+		return flakyDecorator(data)
+	}
+}
+
+func TestRetryRecoversTransientFailures(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+
+	conv := conveyer.New(policyChanSize)
+	conv.RegisterDecorator(handlers.GenericDecoratorFunc(failingDecorator(&attempts, policyRetries)), "in", "out",
+		conveyer.WithRetry(policyRetries, policyBackoff))
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.Send("in", "a"))
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: a", data)
+	require.Equal(t, int32(policyRetries+1), attempts.Load())
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestSkipOnErrorDropsFailedMessages(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(policyChanSize)
+	conv.RegisterDecorator(handlers.GenericDecoratorFunc(flakyDecorator), "in", "out",
+		conveyer.WithSkipOnError())
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.Send("in", "bad"))
+	require.NoError(t, conv.Send("in", "good"))
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: good", data)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestDeadLetterCollectsFailedMessages(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(policyChanSize)
+	conv.RegisterDecorator(handlers.GenericDecoratorFunc(flakyDecorator), "in", "out",
+		conveyer.WithDeadLetter("dlq"), conveyer.WithRetry(1, policyBackoff))
+
+	done := runConveyer(t, conv)
+
+	for _, data := range []string{"bad", "good", "bad"} {
+		require.NoError(t, conv.Send("in", data))
+	}
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: good", data)
+
+	letter, err := conv.RecvDeadLetter("dlq")
+	require.NoError(t, err)
+	require.Equal(t, "bad", letter.Data)
+	require.Equal(t, "decorator[in -> out]", letter.Handler)
+	require.ErrorIs(t, letter.Err, errFlaky)
+
+	data, err = conv.Recv("dlq")
+	require.NoError(t, err)
+	require.Equal(t, "bad", data)
+
+	_, err = conv.TryRecv("dlq")
+	require.ErrorIs(t, err, conveyer.ErrChanEmpty)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestRetryReturnsHandlerErrorWhenCanceled(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+
+	conv := conveyer.New(policyChanSize)
+	conv.RegisterDecorator(handlers.GenericDecoratorFunc(failingDecorator(&attempts, policyRetries)), "in", "out",
+		conveyer.WithRetry(policyRetries, time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conv.Run(ctx)
+	}()
+
+	require.NoError(t, conv.Send("in", "a"))
+
+	require.Eventually(t, func() bool {
+		return attempts.Load() == 1
+	}, time.Second, policyPoll)
+
+	cancel()
+	require.ErrorIs(t, <-done, errFlaky)
+}
@@ -0,0 +1,557 @@
+package conveyer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type ports interface {
+	run(ctx context.Context) error
+	drain()
+	close(finished bool) error
+}
+
+type handlerPorts[T any] struct {
+	handl   handler[T]
+	lane    *lane
+	inlets  inlets[T]
+	outlets outlets[T]
+}
+
+type replicaPorts[T any] struct {
+	handl  handler[T]
+	offers chan offer[T]
+	stop   chan struct{}
+	gone   chan struct{}
+	once   *sync.Once
+	output *channel[T]
+	emit   emitter[T]
+}
+
+type offer[T any] struct {
+	it     item[T]
+	ack    func() error
+	source *channel[T]
+}
+
+type emitter[T any] func(data T) error
+
+type inlets[T any] struct {
+	chans []chan T
+	stop  chan struct{}
+	gone  chan struct{}
+	once  *sync.Once
+	acks  *acker
+}
+
+type outlets[T any] struct {
+	chans []chan T
+	pipes []chan T
+}
+
+type acker struct {
+	mu        sync.Mutex
+	window    int
+	idle      []uint64
+	pokes     []chan struct{}
+	delivered [][]func() error
+	pending   []pendingAck
+}
+
+type pendingAck struct {
+	ack   func() error
+	marks []uint64
+}
+
+const outletClosed = math.MaxUint64
+
+func newAcker(inputs int, outputs int, window int) *acker {
+	acks := &acker{
+		mu:        sync.Mutex{},
+		window:    max(window, 1),
+		idle:      make([]uint64, outputs),
+		pokes:     make([]chan struct{}, outputs),
+		delivered: make([][]func() error, inputs),
+		pending:   nil,
+	}
+
+	for i := range acks.pokes {
+		acks.pokes[i] = make(chan struct{}, 1)
+	}
+
+	return acks
+}
+
+func (a *acker) deliver(input int, ack func() error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.delivered[input] = append(a.delivered[input], ack)
+
+	for len(a.delivered[input]) > a.window {
+		a.enqueue(a.delivered[input][0])
+		a.delivered[input] = a.delivered[input][1:]
+	}
+
+	return a.flush()
+}
+
+func (a *acker) finish() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for input, acks := range a.delivered {
+		for _, ack := range acks {
+			a.enqueue(ack)
+		}
+
+		a.delivered[input] = nil
+	}
+
+	return a.flush()
+}
+
+func (a *acker) enqueue(ack func() error) {
+	a.pending = append(a.pending, pendingAck{ack: ack, marks: slices.Clone(a.idle)})
+
+	for _, poke := range a.pokes {
+		select {
+		case poke <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (a *acker) markIdle(outlet int, closed bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if closed {
+		a.idle[outlet] = outletClosed
+	} else {
+		a.idle[outlet]++
+	}
+
+	return a.flush()
+}
+
+func (a *acker) flush() error {
+	var errs []error
+
+	for len(a.pending) > 0 && a.settled(a.pending[0]) {
+		errs = append(errs, a.pending[0].ack())
+		a.pending = a.pending[1:]
+	}
+
+	return errors.Join(errs...)
+}
+
+func (a *acker) settled(pending pendingAck) bool {
+	for i, mark := range pending.marks {
+		if a.idle[i] <= mark {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *conveyerImpl[T]) openPorts(ctx context.Context, group *errgroup.Group, handl handler[T]) ports {
+	if handl.replicas() > 1 {
+		return c.openReplicaPorts(ctx, group, handl)
+	}
+
+	current := newLane()
+	opened := c.openInlets(ctx, group, handl, current)
+
+	return handlerPorts[T]{
+		handl:   handl,
+		lane:    current,
+		inlets:  opened,
+		outlets: c.openOutlets(ctx, group, handl, opened.acks),
+	}
+}
+
+func (p handlerPorts[T]) run(ctx context.Context) error {
+	return runHandler(withLane(ctx, p.lane), p.handl, p.inlets.chans, p.outlets.chans)
+}
+
+func (p handlerPorts[T]) drain() {
+	p.inlets.drain()
+}
+
+func (p handlerPorts[T]) close(finished bool) error {
+	p.outlets.close()
+
+	return p.inlets.close(finished)
+}
+
+func (c *conveyerImpl[T]) openReplicaPorts(
+	ctx context.Context,
+	group *errgroup.Group,
+	handl handler[T],
+) replicaPorts[T] {
+	input := c.chans[handl.inputIDs[0]]
+	id := handl.outputIDs[0]
+	output := c.chans[id]
+
+	opened := replicaPorts[T]{
+		handl:  handl,
+		offers: make(chan offer[T]),
+		stop:   make(chan struct{}),
+		gone:   make(chan struct{}),
+		once:   &sync.Once{},
+		output: output,
+		emit: func(data T) error {
+			return write(ctx, id, output, handl.stats, data)
+		},
+	}
+
+	group.Go(func() error {
+		return feed(ctx, opened.stop, opened.gone, input, opened.offers)
+	})
+
+	return opened
+}
+
+func (p replicaPorts[T]) run(ctx context.Context) error {
+	return runReplicas(ctx, p.handl, p.offers, p.emit)
+}
+
+func (p replicaPorts[T]) drain() {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+}
+
+func (p replicaPorts[T]) close(bool) error {
+	close(p.gone)
+	p.drain()
+	p.output.release()
+
+	return nil
+}
+
+func feed[T any](
+	ctx context.Context,
+	stop chan struct{},
+	gone chan struct{},
+	channel *channel[T],
+	offers chan offer[T],
+) error {
+	defer close(offers)
+
+	stopped, cancel := stoppable(ctx, stop, gone)
+	defer cancel()
+
+	for {
+		it, ack, err := channel.take(stopped)
+		if err != nil {
+			return nil
+		}
+
+		next := offer[T]{it: it, ack: ack, source: channel}
+
+		select {
+		case <-ctx.Done():
+			next.putBack()
+
+			return nil
+		case <-gone:
+			next.putBack()
+
+			return nil
+		case offers <- next:
+		}
+	}
+}
+
+func (o *offer[T]) putBack() {
+	if o != nil {
+		putBack(o.source, o.it, o.ack)
+	}
+}
+
+func acknowledge(ack func() error) error {
+	if ack == nil {
+		return nil
+	}
+
+	if err := ack(); err != nil {
+		return fmt.Errorf("acknowledge: %w", err)
+	}
+
+	return nil
+}
+
+func stoppable(ctx context.Context, stop chan struct{}, gone chan struct{}) (context.Context, context.CancelFunc) {
+	stopped, cancel := context.WithCancel(ctx)
+
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-gone:
+			cancel()
+		case <-stopped.Done():
+		}
+	}()
+
+	return stopped, cancel
+}
+
+func (c *conveyerImpl[T]) openInlets(
+	ctx context.Context,
+	group *errgroup.Group,
+	handl handler[T],
+	current *lane,
+) inlets[T] {
+	opened := inlets[T]{
+		chans: make([]chan T, len(handl.inputIDs)),
+		stop:  make(chan struct{}),
+		gone:  make(chan struct{}),
+		once:  &sync.Once{},
+		acks:  nil,
+	}
+
+	if c.hasDurableInput(handl) {
+		outputs := len(uniqueIDs(handl.outputIDs))
+		opened.acks = newAcker(len(handl.inputIDs), outputs, handl.replicas())
+	}
+
+	for i, id := range handl.inputIDs {
+		channel := c.chans[id]
+		pipe := make(chan T)
+		opened.chans[i] = pipe
+
+		group.Go(func() error {
+			deliver := func(ack func() error) error {
+				if opened.acks == nil {
+					return ack()
+				}
+
+				return opened.acks.deliver(i, ack)
+			}
+
+			return inlet(ctx, opened.stop, opened.gone, channel, pipe, current, handl.stats, deliver)
+		})
+	}
+
+	return opened
+}
+
+func (i inlets[T]) drain() {
+	i.once.Do(func() {
+		close(i.stop)
+	})
+}
+
+func (i inlets[T]) close(finished bool) error {
+	close(i.gone)
+	i.drain()
+
+	if !finished || i.acks == nil {
+		return nil
+	}
+
+	return i.acks.finish()
+}
+
+func inlet[T any](
+	ctx context.Context,
+	stop chan struct{},
+	gone chan struct{},
+	channel *channel[T],
+	pipe chan T,
+	current *lane,
+	stats *handlerStats,
+	deliver func(ack func() error) error,
+) error {
+	defer close(pipe)
+
+	stopped, cancel := stoppable(ctx, stop, gone)
+	defer cancel()
+
+	for {
+		it, ack, err := channel.take(stopped)
+		if err != nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			putBack(channel, it, ack)
+
+			return nil
+		case <-gone:
+			putBack(channel, it, ack)
+
+			return nil
+		case pipe <- it.data:
+			current.track(it.deadline)
+			stats.handedOff(time.Now())
+		}
+
+		if ack == nil {
+			continue
+		}
+
+		if err := deliver(ack); err != nil {
+			return fmt.Errorf("acknowledge: %w", err)
+		}
+	}
+}
+
+func putBack[T any](channel *channel[T], it item[T], ack func() error) {
+	if ack == nil {
+		channel.requeue(it)
+	}
+}
+
+func (c *conveyerImpl[T]) openOutlets(
+	ctx context.Context,
+	group *errgroup.Group,
+	handl handler[T],
+	acks *acker,
+) outlets[T] {
+	opened := outlets[T]{
+		chans: make([]chan T, len(handl.outputIDs)),
+		pipes: nil,
+	}
+
+	byID := make(map[string]chan T, len(handl.outputIDs))
+
+	for i, id := range handl.outputIDs {
+		if pipe, ok := byID[id]; ok {
+			opened.chans[i] = pipe
+
+			continue
+		}
+
+		channel := c.chans[id]
+		pipe := make(chan T)
+		index := len(opened.pipes)
+		byID[id] = pipe
+		opened.chans[i] = pipe
+		opened.pipes = append(opened.pipes, pipe)
+
+		group.Go(func() error {
+			if acks == nil {
+				return outlet(ctx, id, pipe, channel, handl.stats)
+			}
+
+			return ackedOutlet(ctx, id, pipe, channel, handl.stats, acks, index)
+		})
+	}
+
+	return opened
+}
+
+func (o outlets[T]) close() {
+	for _, pipe := range o.pipes {
+		close(pipe)
+	}
+}
+
+func outlet[T any](
+	ctx context.Context,
+	id string,
+	pipe chan T,
+	channel *channel[T],
+	stats *handlerStats,
+) error {
+	defer channel.release()
+
+	for data := range pipe {
+		if err := write(ctx, id, channel, stats, data); err != nil {
+			return ignoreCanceled(err)
+		}
+	}
+
+	return nil
+}
+
+func ackedOutlet[T any](
+	ctx context.Context,
+	id string,
+	pipe chan T,
+	channel *channel[T],
+	stats *handlerStats,
+	acks *acker,
+	index int,
+) error {
+	defer channel.release()
+
+	for {
+		if err := acks.markIdle(index, false); err != nil {
+			return fmt.Errorf("acknowledge: %w", err)
+		}
+
+		select {
+		case <-acks.pokes[index]:
+		case data, ok := <-pipe:
+			if !ok {
+				if err := acks.markIdle(index, true); err != nil {
+					return fmt.Errorf("acknowledge: %w", err)
+				}
+
+				return nil
+			}
+
+			if err := write(ctx, id, channel, stats, data); err != nil {
+				return ignoreCanceled(err)
+			}
+		}
+	}
+}
+
+func write[T any](
+	ctx context.Context,
+	id string,
+	channel *channel[T],
+	stats *handlerStats,
+	data T,
+) error {
+	stats.emitted(time.Now())
+
+	if err := channel.send(ctx, data); err != nil {
+		return fmt.Errorf("channel %q: %w", id, err)
+	}
+
+	return nil
+}
+
+func ignoreCanceled(err error) error {
+	if errors.Is(err, ErrCanceled) {
+		return nil
+	}
+
+	return err
+}
+
+func (c *conveyerImpl[T]) hasDurableInput(handl handler[T]) bool {
+	for _, id := range handl.inputIDs {
+		if c.chans[id].durable != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func uniqueIDs(ids []string) map[string]struct{} {
+	unique := make(map[string]struct{}, len(ids))
+
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+
+	return unique
+}
@@ -0,0 +1,28 @@
+package conveyer
+
+import "context"
+
+func WithPriority(priority int) ChannelOption {
+	return func(opts *channelOptions) {
+		opts.priority = priority
+	}
+}
+
+func (c *conveyerImpl[T]) priorities(ids []string) []int {
+	priorities := make([]int, len(ids))
+
+	for i, id := range ids {
+		priorities[i] = c.channelOptionsFor(id).priority
+	}
+
+	return priorities
+}
+
+func InputPriorities(ctx context.Context) []int {
+	policy, ok := policyFrom(ctx)
+	if !ok {
+		return nil
+	}
+
+	return append([]int{}, policy.priorities...)
+}
@@ -1,82 +1,177 @@
 package conveyer
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
-func (c *conveyerImpl) initChannel(id string) {
-	c.chans[id] = make(chan string, c.size)
+func (c *conveyerImpl[T]) initChannel(id string) {
+	options := c.channelOptionsFor(id)
+
+	c.chans[id] = newChannel[T](options.capacity)
+	c.chans[id].overflow = options.overflow
+	c.chans[id].ttl = options.ttl
+	c.chans[id].sink = c.ensureExpired(options)
 }
 
-func (c *conveyerImpl) ensureChannel(id string) {
+func (c *conveyerImpl[T]) ensureChannel(id string) {
 	if _, ok := c.chans[id]; !ok {
 		c.initChannel(id)
+		c.adopt(id)
 	}
 }
 
-func (c *conveyerImpl) RegisterDecorator(
-	fnHandler func(context.Context, chan string, chan string) error,
+func (c *conveyerImpl[T]) RegisterDecorator(
+	fnHandler func(context.Context, chan T, chan T) error,
 	input string,
 	output string,
+	opts ...HandlerOption,
 ) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	options := newHandlerOptions(opts)
+	c.ensureDeadLetter(options)
+
 	c.ensureChannel(input)
 	c.ensureChannel(output)
 
-	c.handlers = append(c.handlers, handler{
+	c.addHandler(handler[T]{
 		kind:          hDecorator,
 		fnDecorator:   fnHandler,
 		fnMultiplexer: nil,
 		fnSeparator:   nil,
+		fnStage:       nil,
 		inputIDs:      []string{input},
 		outputIDs:     []string{output},
+		options:       options,
+		name:          options.name,
+		stats:         newHandlerStats(),
 	})
 }
 
-func (c *conveyerImpl) RegisterMultiplexer(
-	fnHandler func(context.Context, []chan string, chan string) error,
+func (c *conveyerImpl[T]) RegisterMultiplexer(
+	fnHandler func(context.Context, []chan T, chan T) error,
 	inputs []string,
 	output string,
+	opts ...HandlerOption,
 ) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	options := newHandlerOptions(opts)
+	c.ensureDeadLetter(options)
+
 	for _, id := range inputs {
 		c.ensureChannel(id)
 	}
 
 	c.ensureChannel(output)
 
-	c.handlers = append(c.handlers, handler{
+	c.addHandler(handler[T]{
 		kind:          hMultiplexer,
 		fnDecorator:   nil,
 		fnMultiplexer: fnHandler,
 		fnSeparator:   nil,
+		fnStage:       nil,
 		inputIDs:      inputs,
 		outputIDs:     []string{output},
+		options:       options,
+		name:          options.name,
+		stats:         newHandlerStats(),
 	})
 }
 
-func (c *conveyerImpl) RegisterSeparator(
-	fnHandler func(context.Context, chan string, []chan string) error,
+func (c *conveyerImpl[T]) RegisterSeparator(
+	fnHandler func(context.Context, chan T, []chan T) error,
 	input string,
 	outputs []string,
+	opts ...HandlerOption,
 ) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	options := newHandlerOptions(opts)
+	c.ensureDeadLetter(options)
+
 	c.ensureChannel(input)
 
 	for _, id := range outputs {
 		c.ensureChannel(id)
 	}
 
-	c.handlers = append(c.handlers, handler{
+	c.addHandler(handler[T]{
 		kind:          hSeparator,
 		fnDecorator:   nil,
 		fnMultiplexer: nil,
 		fnSeparator:   fnHandler,
+		fnStage:       nil,
 		inputIDs:      []string{input},
 		outputIDs:     outputs,
+		options:       options,
+		name:          options.name,
+		stats:         newHandlerStats(),
+	})
+}
+
+func (c *conveyerImpl[T]) RegisterStage(
+	fnHandler func(context.Context, []chan T, []chan T) error,
+	inputs []string,
+	outputs []string,
+	opts ...HandlerOption,
+) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	options := newHandlerOptions(opts)
+	c.ensureDeadLetter(options)
+
+	for _, id := range inputs {
+		c.ensureChannel(id)
+	}
+
+	for _, id := range outputs {
+		c.ensureChannel(id)
+	}
+
+	c.addHandler(handler[T]{
+		kind:          hStage,
+		fnDecorator:   nil,
+		fnMultiplexer: nil,
+		fnSeparator:   nil,
+		fnStage:       fnHandler,
+		inputIDs:      inputs,
+		outputIDs:     outputs,
+		options:       options,
+		name:          options.name,
+		stats:         newHandlerStats(),
 	})
 }
+
+func (c *conveyerImpl[T]) addHandler(handl handler[T]) {
+	if handl.name == "" {
+		handl.name = handl.String()
+	}
+
+	base := handl.name
+
+	for suffix := 2; c.hasHandler(handl.name); suffix++ {
+		handl.name = fmt.Sprintf("%s#%d", base, suffix)
+	}
+
+	c.handlers = append(c.handlers, handl)
+
+	if c.running() {
+		c.attach(handl)
+	}
+}
+
+func (c *conveyerImpl[T]) hasHandler(name string) bool {
+	for _, handl := range c.handlers {
+		if handl.name == name {
+			return true
+		}
+	}
+
+	return false
+}
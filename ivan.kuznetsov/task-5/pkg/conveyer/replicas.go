@@ -0,0 +1,344 @@
+package conveyer
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const noSequence = -1
+
+const (
+	balancerDone = iota
+	balancerOffer
+	balancerCases
+)
+
+const (
+	replicaIn = iota
+	replicaOut
+	replicaDropped
+	replicaExited
+	replicaCases
+)
+
+type replica[T any] struct {
+	in       chan T
+	out      chan T
+	dropped  chan struct{}
+	exited   chan struct{}
+	lane     *lane
+	err      error
+	gone     bool
+	seq      int
+	inflight func() error
+}
+
+type sequenceEvent[T any] struct {
+	data    T
+	ack     func() error
+	dropped bool
+}
+
+type balancer[T any] struct {
+	replicas []*replica[T]
+	cases    []reflect.SelectCase
+	offers   reflect.Value
+	pending  *offer[T]
+	emit     emitter[T]
+	stats    *handlerStats
+	ordered  bool
+	closed   bool
+	live     int
+	seq      int
+	next     int
+	parked   map[int]sequenceEvent[T]
+}
+
+func WithReplicas(replicas int) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.replicas = replicas
+	}
+}
+
+func (h handler[T]) replicas() int {
+	if h.kind != hDecorator || h.options.replicas < 1 {
+		return 1
+	}
+
+	return h.options.replicas
+}
+
+func WithOrdered() HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.ordered = true
+	}
+}
+
+func runReplicas[T any](ctx context.Context, handl handler[T], offers chan offer[T], emit emitter[T]) error {
+	group, ctx := errgroup.WithContext(ctx)
+	ordered := handl.options.ordered
+	replicas := make([]*replica[T], handl.replicas())
+
+	for i := range replicas {
+		replica := newReplica[T]()
+		replicas[i] = replica
+
+		replicaCtx := withLane(ctx, replica.lane)
+		if ordered {
+			replicaCtx = withDropHook(replicaCtx, replica.notifyDropped(ctx))
+		}
+
+		group.Go(func() error {
+			defer close(replica.exited)
+
+			replica.err = handl.fnDecorator(replicaCtx, replica.in, replica.out)
+
+			return replica.err
+		})
+	}
+
+	group.Go(func() error {
+		return newBalancer(ctx, replicas, offers, emit, handl.stats, ordered).run(ctx)
+	})
+
+	return group.Wait()
+}
+
+func newReplica[T any]() *replica[T] {
+	return &replica[T]{
+		in:       make(chan T),
+		out:      make(chan T),
+		dropped:  make(chan struct{}),
+		exited:   make(chan struct{}),
+		lane:     newLane(),
+		err:      nil,
+		gone:     false,
+		seq:      noSequence,
+		inflight: nil,
+	}
+}
+
+func (r *replica[T]) notifyDropped(ctx context.Context) func() {
+	return func() {
+		select {
+		case <-ctx.Done():
+		case r.dropped <- struct{}{}:
+		}
+	}
+}
+
+func newBalancer[T any](
+	ctx context.Context,
+	replicas []*replica[T],
+	offers chan offer[T],
+	emit emitter[T],
+	stats *handlerStats,
+	ordered bool,
+) *balancer[T] {
+	cases := make([]reflect.SelectCase, 0, balancerCases+len(replicas)*replicaCases)
+	cases = append(cases, recvCase(reflect.ValueOf(ctx.Done())), recvCase(reflect.Value{}))
+
+	for _, replica := range replicas {
+		cases = append(cases,
+			reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.Value{}, Send: reflect.Value{}},
+			recvCase(reflect.ValueOf(replica.out)),
+			recvCase(reflect.ValueOf(replica.dropped)),
+			recvCase(reflect.ValueOf(replica.exited)),
+		)
+	}
+
+	return &balancer[T]{
+		replicas: replicas,
+		cases:    cases,
+		offers:   reflect.ValueOf(offers),
+		pending:  nil,
+		emit:     emit,
+		stats:    stats,
+		ordered:  ordered,
+		closed:   false,
+		live:     len(replicas),
+		seq:      0,
+		next:     0,
+		parked:   make(map[int]sequenceEvent[T]),
+	}
+}
+
+func recvCase(ch reflect.Value) reflect.SelectCase {
+	return reflect.SelectCase{Dir: reflect.SelectRecv, Chan: ch, Send: reflect.Value{}}
+}
+
+func (b *balancer[T]) run(ctx context.Context) error {
+	defer func() {
+		b.pending.putBack()
+	}()
+
+	for b.live > 0 {
+		b.arm()
+
+		chosen, value, ok := reflect.Select(b.cases)
+
+		switch chosen {
+		case balancerDone:
+			return nil
+		case balancerOffer:
+			b.receive(value, ok)
+		default:
+			index := (chosen - balancerCases) / replicaCases
+			event := (chosen - balancerCases) % replicaCases
+
+			if err := b.handle(ctx, index, event, value); err != nil {
+				return ignoreCanceled(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *balancer[T]) arm() {
+	b.cases[balancerOffer].Chan = reflect.Value{}
+	if b.pending == nil && !b.closed {
+		b.cases[balancerOffer].Chan = b.offers
+	}
+
+	var data reflect.Value
+	if b.pending != nil {
+		data = reflect.ValueOf(&b.pending.it.data).Elem()
+	}
+
+	for i, replica := range b.replicas {
+		handOff := &b.cases[balancerCases+i*replicaCases+replicaIn]
+		handOff.Chan, handOff.Send = reflect.Value{}, reflect.Value{}
+
+		if data.IsValid() && !replica.gone {
+			handOff.Chan, handOff.Send = reflect.ValueOf(replica.in), data
+		}
+	}
+}
+
+func (b *balancer[T]) receive(value reflect.Value, ok bool) {
+	if !ok {
+		b.closed = true
+
+		for _, replica := range b.replicas {
+			close(replica.in)
+		}
+
+		return
+	}
+
+	next, _ := value.Interface().(offer[T])
+	b.pending = &next
+}
+
+func (b *balancer[T]) handle(ctx context.Context, index int, event int, value reflect.Value) error {
+	replica := b.replicas[index]
+
+	switch event {
+	case replicaIn:
+		replica.lane.track(b.pending.it.deadline)
+		b.stats.handedOff(time.Now())
+
+		if err := b.complete(replica, true); err != nil {
+			return err
+		}
+
+		replica.seq, replica.inflight = b.assign(), b.pending.ack
+		b.pending = nil
+
+		return nil
+	case replicaOut:
+		data, _ := value.Interface().(T)
+
+		return b.output(replica, data)
+	case replicaDropped:
+		return b.complete(replica, true)
+	default:
+		b.retire(index)
+
+		return b.complete(replica, replica.err == nil && ctx.Err() == nil)
+	}
+}
+
+func (b *balancer[T]) assign() int {
+	if !b.ordered {
+		return noSequence
+	}
+
+	b.seq++
+
+	return b.seq - 1
+}
+
+func (b *balancer[T]) output(replica *replica[T], data T) error {
+	if replica.seq == noSequence {
+		return b.emit(data)
+	}
+
+	b.parked[replica.seq] = sequenceEvent[T]{
+		data:    data,
+		ack:     replica.inflight,
+		dropped: false,
+	}
+	replica.seq, replica.inflight = noSequence, nil
+
+	return b.flush()
+}
+
+func (b *balancer[T]) complete(replica *replica[T], settled bool) error {
+	ack := replica.inflight
+	if !settled {
+		ack = nil
+	}
+
+	replica.inflight = nil
+
+	if replica.seq == noSequence {
+		return acknowledge(ack)
+	}
+
+	var zero T
+
+	b.parked[replica.seq] = sequenceEvent[T]{
+		data:    zero,
+		ack:     ack,
+		dropped: true,
+	}
+	replica.seq = noSequence
+
+	return b.flush()
+}
+
+func (b *balancer[T]) flush() error {
+	for {
+		ready, ok := b.parked[b.next]
+		if !ok {
+			return nil
+		}
+
+		delete(b.parked, b.next)
+		b.next++
+
+		if !ready.dropped {
+			if err := b.emit(ready.data); err != nil {
+				return err
+			}
+		}
+
+		if err := acknowledge(ready.ack); err != nil {
+			return err
+		}
+	}
+}
+
+func (b *balancer[T]) retire(index int) {
+	b.replicas[index].gone = true
+	b.live--
+
+	for event := range replicaCases {
+		b.cases[balancerCases+index*replicaCases+event].Chan = reflect.Value{}
+	}
+}
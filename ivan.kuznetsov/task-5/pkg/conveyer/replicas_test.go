@@ -0,0 +1,161 @@
+package conveyer_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	replicasMessages  = 200
+	slowDecoratorCost = 200 * time.Microsecond
+)
+
+func slowDecorator(data string) (string, error) {
+	time.Sleep(slowDecoratorCost)
+
+	return "decorated: " + data, nil
+}
+
+func pumpMessages(t testing.TB, conv conveyer.Conveyer[string], count int) {
+	t.Helper()
+
+	go func() {
+		for i := range count {
+			if err := conv.Send("in", strconv.Itoa(i)); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func TestOrderedReplicasKeepInputOrder(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(replicasMessages)
+	conv.RegisterDecorator(
+		handlers.GenericDecoratorFunc(slowDecorator), "in", "out",
+		conveyer.WithReplicas(4), conveyer.WithOrdered(),
+	)
+
+	done := runConveyer(t, conv)
+	pumpMessages(t, conv, replicasMessages)
+
+	for i := range replicasMessages {
+		data, err := conv.Recv("out")
+		require.NoError(t, err)
+		require.Equal(t, "decorated: "+strconv.Itoa(i), data, "message %d is out of order", i)
+	}
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestOrderedReplicasSkipDroppedMessages(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(replicasMessages)
+	conv.RegisterDecorator(
+		handlers.PrefixDecoratorFunc, "in", "out",
+		conveyer.WithReplicas(3), conveyer.WithOrdered(), conveyer.WithSkipOnError(),
+	)
+
+	done := runConveyer(t, conv)
+
+	for _, data := range []string{"a", "no decorator", "b", "no decorator", "c"} {
+		require.NoError(t, conv.Send("in", data))
+	}
+
+	for _, expected := range []string{"decorated: a", "decorated: b", "decorated: c"} {
+		data, err := conv.Recv("out")
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	}
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestReplicasAreRejectedForNonDecorators(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		register func(conv conveyer.Conveyer[string])
+	}{
+		{
+			name: "separator",
+			register: func(conv conveyer.Conveyer[string]) {
+				conv.RegisterSeparator(handlers.SeparatorFunc, "in", []string{"out"}, conveyer.WithReplicas(2))
+			},
+		},
+		{
+			name: "multiplexer",
+			register: func(conv conveyer.Conveyer[string]) {
+				conv.RegisterMultiplexer(handlers.MultiplexerFunc, []string{"in"}, "out", conveyer.WithReplicas(2))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			conv := conveyer.New(1)
+			test.register(conv)
+
+			require.ErrorIs(t, conv.Validate(), conveyer.ErrReplicasRequireDecorator)
+			require.ErrorIs(t, conv.Run(context.Background()), conveyer.ErrReplicasRequireDecorator)
+		})
+	}
+}
+
+func BenchmarkDecoratorReplicas(b *testing.B) {
+	cases := []struct {
+		name     string
+		replicas int
+		ordered  bool
+	}{
+		{name: "baseline", replicas: 1, ordered: false},
+		{name: "replicas-4", replicas: 4, ordered: false},
+		{name: "replicas-8", replicas: 8, ordered: false},
+		{name: "ordered-4", replicas: 4, ordered: true},
+		{name: "ordered-8", replicas: 8, ordered: true},
+	}
+
+	for _, bc := range cases {
+		b.Run(bc.name, func(b *testing.B) {
+			opts := []conveyer.HandlerOption{conveyer.WithReplicas(bc.replicas)}
+			if bc.ordered {
+				opts = append(opts, conveyer.WithOrdered())
+			}
+
+			conv := conveyer.New(bc.replicas)
+			conv.RegisterDecorator(handlers.GenericDecoratorFunc(slowDecorator), "in", "out", opts...)
+
+			done := runConveyer(b, conv)
+
+			b.ResetTimer()
+			pumpMessages(b, conv, b.N)
+
+			for range b.N {
+				if _, err := conv.Recv("out"); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.StopTimer()
+
+			if err := conv.Shutdown(context.Background()); err != nil {
+				b.Fatal(err)
+			}
+
+			<-done
+		})
+	}
+}
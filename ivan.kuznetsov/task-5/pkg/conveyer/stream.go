@@ -0,0 +1,39 @@
+package conveyer
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+func (c *conveyerImpl[T]) All(id string) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			data, err := c.Recv(id)
+			if err != nil {
+				return
+			}
+
+			if !yield(data) {
+				return
+			}
+		}
+	}
+}
+
+func (c *conveyerImpl[T]) Drain(ctx context.Context, id string) ([]T, error) {
+	var drained []T
+
+	for {
+		data, err := c.RecvContext(ctx, id)
+		if errors.Is(err, ErrChanClosed) {
+			return drained, nil
+		}
+
+		if err != nil {
+			return drained, err
+		}
+
+		drained = append(drained, data)
+	}
+}
@@ -0,0 +1,89 @@
+package conveyer_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	streamChanSize = 4
+	streamWait     = 10 * time.Millisecond
+	streamPoll     = time.Millisecond
+)
+
+func stoppedConveyer(t *testing.T, sent ...string) conveyer.Conveyer[string] {
+	t.Helper()
+
+	conv := conveyer.New(streamChanSize)
+	conv.RegisterSeparator(handlers.BroadcastFunc[string], "in", []string{"left", "right"})
+	done := runConveyer(t, conv)
+
+	for _, data := range sent {
+		require.NoError(t, conv.Send("in", data))
+	}
+
+	require.Eventually(t, func() bool {
+		return !errors.Is(conv.Shutdown(context.Background()), conveyer.ErrNotStarted)
+	}, time.Second, streamPoll)
+	require.NoError(t, <-done)
+
+	return conv
+}
+
+func TestAllYieldsUntilChannelCloses(t *testing.T) {
+	t.Parallel()
+
+	conv := stoppedConveyer(t, "a", "b", "c")
+
+	require.Equal(t, []string{"a", "b", "c"}, slices.Collect(conv.All("left")))
+	require.Empty(t, slices.Collect(conv.All("left")))
+	require.Empty(t, slices.Collect(conv.All("missing")))
+
+	for data := range conv.All("right") {
+		require.Equal(t, "a", data)
+
+		break
+	}
+
+	data, err := conv.Recv("right")
+	require.NoError(t, err)
+	require.Equal(t, "b", data)
+}
+
+func TestDrainCollectsUntilChannelCloses(t *testing.T) {
+	t.Parallel()
+
+	conv := stoppedConveyer(t, "a", "b")
+
+	drained, err := conv.Drain(context.Background(), "left")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, drained)
+
+	_, err = conv.Recv("left")
+	require.ErrorIs(t, err, conveyer.ErrChanClosed)
+
+	_, err = conv.Drain(context.Background(), "missing")
+	require.ErrorIs(t, err, conveyer.ErrChanNotFound)
+}
+
+func TestDrainStopsOnContext(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(streamChanSize)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+	require.NoError(t, conv.Send("in", "a"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamWait)
+	defer cancel()
+
+	drained, err := conv.Drain(ctx, "in")
+	require.ErrorIs(t, err, conveyer.ErrCanceled)
+	require.Equal(t, []string{"a"}, drained)
+}
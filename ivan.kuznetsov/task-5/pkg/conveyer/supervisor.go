@@ -0,0 +1,454 @@
+package conveyer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrRestartBudgetExhausted = errors.New("restart budget exhausted")
+	ErrInvalidRestartBackoff  = errors.New("restart backoff must be positive")
+	ErrInvalidRestartBudget   = errors.New("restart budget must be positive")
+)
+
+type Strategy int
+
+const (
+	StrategyNone Strategy = iota
+	StrategyOneForOne
+	StrategyAllForOne
+)
+
+const (
+	defaultRestartBackoff    = 10 * time.Millisecond
+	defaultRestartMaxBackoff = time.Second
+	defaultMaxRestarts       = 3
+	defaultRestartWindow     = 5 * time.Second
+	supervisorEventsSize     = 64
+)
+
+type SupervisorOption func(*supervisorConfig)
+
+type supervisorConfig struct {
+	strategy    Strategy
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	maxRestarts int
+	window      time.Duration
+}
+
+type SupervisorEvent struct {
+	Handler  string
+	Err      error
+	Restarts int
+	Backoff  time.Duration
+	GaveUp   bool
+}
+
+type restartBudget struct {
+	mu       sync.Mutex
+	config   supervisorConfig
+	restarts []time.Time
+}
+
+type allForOne struct {
+	mu         sync.Mutex
+	cond       *sync.Cond
+	parent     context.Context
+	ctx        context.Context
+	cancel     context.CancelFunc
+	generation int
+	members    int
+	waiting    int
+	restarting bool
+	failure    error
+}
+
+func (s Strategy) String() string {
+	switch s {
+	case StrategyNone:
+		return "none"
+	case StrategyOneForOne:
+		return "one-for-one"
+	case StrategyAllForOne:
+		return "all-for-one"
+	default:
+		return "unknown"
+	}
+}
+
+func WithRestartBackoff(initial time.Duration, maximum time.Duration) SupervisorOption {
+	return func(config *supervisorConfig) {
+		config.backoff = initial
+		config.maxBackoff = max(initial, maximum)
+	}
+}
+
+func WithRestartBudget(maxRestarts int, window time.Duration) SupervisorOption {
+	return func(config *supervisorConfig) {
+		config.maxRestarts = maxRestarts
+		config.window = window
+	}
+}
+
+func (c *conveyerImpl[T]) Supervise(strategy Strategy, opts ...SupervisorOption) <-chan SupervisorEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config := supervisorConfig{
+		strategy:    strategy,
+		backoff:     defaultRestartBackoff,
+		maxBackoff:  defaultRestartMaxBackoff,
+		maxRestarts: defaultMaxRestarts,
+		window:      defaultRestartWindow,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	c.supervisor = config
+
+	if c.events == nil {
+		c.events = make(chan SupervisorEvent, supervisorEventsSize)
+	}
+
+	return c.events
+}
+
+func (config supervisorConfig) validate() []error {
+	var errs []error
+
+	if config.backoff <= 0 {
+		errs = append(errs, fmt.Errorf("%w: got %s", ErrInvalidRestartBackoff, config.backoff))
+	}
+
+	if config.maxRestarts < 1 {
+		errs = append(errs, fmt.Errorf("%w: got %d restarts", ErrInvalidRestartBudget, config.maxRestarts))
+	}
+
+	if config.window <= 0 {
+		errs = append(errs, fmt.Errorf("%w: got %s window", ErrInvalidRestartBudget, config.window))
+	}
+
+	return errs
+}
+
+func (c *conveyerImpl[T]) closeEvents() {
+	if c.events == nil {
+		return
+	}
+
+	close(c.events)
+	c.events = nil
+}
+
+func (c *conveyerImpl[T]) emit(event SupervisorEvent) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.events == nil {
+		return
+	}
+
+	select {
+	case c.events <- event:
+	default:
+	}
+}
+
+func newRestartBudget(config supervisorConfig) *restartBudget {
+	return &restartBudget{
+		mu:       sync.Mutex{},
+		config:   config,
+		restarts: nil,
+	}
+}
+
+func (b *restartBudget) allow(now time.Time) (time.Duration, int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	recent := b.restarts[:0]
+
+	for _, restart := range b.restarts {
+		if now.Sub(restart) < b.config.window {
+			recent = append(recent, restart)
+		}
+	}
+
+	b.restarts = recent
+
+	if len(b.restarts) >= b.config.maxRestarts {
+		return 0, len(b.restarts), false
+	}
+
+	b.restarts = append(b.restarts, now)
+
+	return b.delay(len(b.restarts) - 1), len(b.restarts), true
+}
+
+func (b *restartBudget) delay(attempt int) time.Duration {
+	delay := b.config.backoff
+
+	for range attempt {
+		if delay > b.config.maxBackoff/2 {
+			return b.config.maxBackoff
+		}
+
+		delay *= 2
+	}
+
+	return delay
+}
+
+func sleep(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (c *conveyerImpl[T]) supervise(
+	ctx context.Context,
+	handl handler[T],
+	budget *restartBudget,
+	group *allForOne,
+	run func(context.Context) error,
+) error {
+	switch c.supervisor.strategy {
+	case StrategyOneForOne:
+		return c.oneForOne(ctx, handl, budget, run)
+	case StrategyAllForOne:
+		return c.allForOne(ctx, handl, budget, group, run)
+	default:
+		err := run(ctx)
+		if err != nil {
+			handl.stats.errors.Add(1)
+		}
+
+		return err
+	}
+}
+
+func (c *conveyerImpl[T]) oneForOne(
+	ctx context.Context,
+	handl handler[T],
+	budget *restartBudget,
+	run func(context.Context) error,
+) error {
+	for {
+		err := run(ctx)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		handl.stats.errors.Add(1)
+
+		if !c.restart(ctx, handl.name, err, budget) {
+			return fmt.Errorf("%w: %s: %w", ErrRestartBudgetExhausted, handl.name, err)
+		}
+	}
+}
+
+func (c *conveyerImpl[T]) restart(ctx context.Context, name string, err error, budget *restartBudget) bool {
+	backoff, restarts, ok := budget.allow(time.Now())
+
+	c.emit(SupervisorEvent{
+		Handler:  name,
+		Err:      err,
+		Restarts: restarts,
+		Backoff:  backoff,
+		GaveUp:   !ok,
+	})
+
+	return ok && sleep(ctx, backoff)
+}
+
+func newAllForOne(ctx context.Context, members int) *allForOne {
+	group := &allForOne{
+		mu:         sync.Mutex{},
+		cond:       nil,
+		parent:     ctx,
+		ctx:        nil,
+		cancel:     nil,
+		generation: 0,
+		members:    members,
+		waiting:    0,
+		restarting: false,
+		failure:    nil,
+	}
+
+	group.cond = sync.NewCond(&group.mu)
+	group.ctx, group.cancel = context.WithCancel(ctx)
+
+	context.AfterFunc(ctx, func() {
+		group.mu.Lock()
+		defer group.mu.Unlock()
+
+		group.cond.Broadcast()
+	})
+
+	return group
+}
+
+func (c *conveyerImpl[T]) allForOne(
+	ctx context.Context,
+	handl handler[T],
+	budget *restartBudget,
+	group *allForOne,
+	run func(context.Context) error,
+) error {
+	generation, genCtx := group.current()
+
+	for {
+		err := runGeneration(ctx, genCtx, run)
+
+		switch {
+		case ctx.Err() != nil:
+			group.leave()
+
+			return err
+		case err == nil && genCtx.Err() == nil:
+			group.leave()
+
+			return nil
+		case err != nil && genCtx.Err() == nil:
+			handl.stats.errors.Add(1)
+
+			if group.fail() {
+				if restartErr := c.lead(ctx, handl.name, err, budget, group); restartErr != nil {
+					return restartErr
+				}
+
+				generation, genCtx = group.current()
+
+				continue
+			}
+		}
+
+		next, nextCtx, failure := group.next(generation)
+		if failure != nil {
+			return failure
+		}
+
+		generation, genCtx = next, nextCtx
+	}
+}
+
+func runGeneration(ctx context.Context, genCtx context.Context, run func(context.Context) error) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(genCtx, cancel)
+	defer stop()
+
+	return run(runCtx)
+}
+
+func (c *conveyerImpl[T]) lead(
+	ctx context.Context,
+	name string,
+	err error,
+	budget *restartBudget,
+	group *allForOne,
+) error {
+	group.awaitFollowers()
+
+	if !c.restart(ctx, name, err, budget) {
+		failure := fmt.Errorf("%w: %s: %w", ErrRestartBudgetExhausted, name, err)
+		group.terminate(failure)
+
+		return failure
+	}
+
+	group.advance()
+
+	return nil
+}
+
+func (g *allForOne) current() (int, context.Context) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.generation, g.ctx
+}
+
+func (g *allForOne) fail() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.restarting {
+		return false
+	}
+
+	g.restarting = true
+	g.cancel()
+
+	return true
+}
+
+func (g *allForOne) awaitFollowers() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for g.waiting < g.members-1 && g.parent.Err() == nil {
+		g.cond.Wait()
+	}
+}
+
+func (g *allForOne) advance() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.ctx, g.cancel = context.WithCancel(g.parent)
+	g.generation++
+	g.waiting = 0
+	g.restarting = false
+	g.cond.Broadcast()
+}
+
+func (g *allForOne) terminate(failure error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.failure = failure
+	g.cond.Broadcast()
+}
+
+func (g *allForOne) next(generation int) (int, context.Context, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.waiting++
+	g.cond.Broadcast()
+
+	for g.generation == generation && g.failure == nil && g.parent.Err() == nil {
+		g.cond.Wait()
+	}
+
+	return g.generation, g.ctx, g.failure
+}
+
+func (g *allForOne) join() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.members++
+}
+
+func (g *allForOne) leave() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.members--
+	g.cond.Broadcast()
+}
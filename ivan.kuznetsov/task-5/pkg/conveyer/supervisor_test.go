@@ -0,0 +1,221 @@
+package conveyer_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	supervisorChanSize = 4
+	supervisorBackoff  = time.Millisecond
+	supervisorWindow   = time.Minute
+	supervisorFailures = 70
+)
+
+func TestOneForOneRestartsOnlyFailedHandler(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(supervisorChanSize)
+	events := conv.Supervise(conveyer.StrategyOneForOne, conveyer.WithRestartBackoff(supervisorBackoff, supervisorBackoff))
+	conv.RegisterDecorator(handlers.GenericDecoratorFunc(flakyDecorator), "in", "out", conveyer.WithName("flaky"))
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "side-in", "side-out", conveyer.WithName("steady"))
+
+	done := runConveyer(t, conv)
+
+	for _, data := range []string{"a", "bad", "b"} {
+		require.NoError(t, conv.Send("in", data))
+	}
+
+	for _, expected := range []string{"decorated: a", "decorated: b"} {
+		data, err := conv.Recv("out")
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	}
+
+	event := <-events
+	require.Equal(t, "flaky", event.Handler)
+	require.ErrorIs(t, event.Err, errFlaky)
+	require.Equal(t, 1, event.Restarts)
+	require.Equal(t, supervisorBackoff, event.Backoff)
+	require.False(t, event.GaveUp)
+
+	require.NoError(t, conv.Send("side-in", "c"))
+
+	data, err := conv.Recv("side-out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: c", data)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestSuperviseSharesEventsUntilConveyerFinishes(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(supervisorChanSize)
+	first := conv.Supervise(conveyer.StrategyOneForOne)
+	second := conv.Supervise(conveyer.StrategyOneForOne,
+		conveyer.WithRestartBackoff(supervisorBackoff, supervisorBackoff))
+	require.Equal(t, first, second)
+
+	conv.RegisterDecorator(handlers.GenericDecoratorFunc(flakyDecorator), "in", "out", conveyer.WithName("flaky"))
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.Send("in", "bad"))
+
+	event := <-first
+	require.Equal(t, "flaky", event.Handler)
+	require.Equal(t, supervisorBackoff, event.Backoff)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+
+	_, open := <-first
+	require.False(t, open)
+
+	third := conv.Supervise(conveyer.StrategyOneForOne)
+	require.NotEqual(t, first, third)
+}
+
+func TestSupervisorFailsOnceBudgetIsExhausted(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(supervisorChanSize)
+	events := conv.Supervise(conveyer.StrategyOneForOne,
+		conveyer.WithRestartBackoff(supervisorBackoff, supervisorBackoff),
+		conveyer.WithRestartBudget(1, supervisorWindow))
+	conv.RegisterDecorator(handlers.GenericDecoratorFunc(flakyDecorator), "in", "out", conveyer.WithName("flaky"))
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.Send("in", "bad"))
+	require.NoError(t, conv.Send("in", "bad"))
+
+	err := <-done
+	require.ErrorIs(t, err, conveyer.ErrRestartBudgetExhausted)
+	require.ErrorIs(t, err, errFlaky)
+
+	require.False(t, (<-events).GaveUp)
+	require.True(t, (<-events).GaveUp)
+}
+
+func TestRestartBackoffStopsGrowingAtMaximum(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(supervisorChanSize)
+	events := conv.Supervise(conveyer.StrategyOneForOne,
+		conveyer.WithRestartBackoff(time.Nanosecond, 2*time.Nanosecond),
+		conveyer.WithRestartBudget(supervisorFailures, supervisorWindow))
+	conv.RegisterDecorator(handlers.GenericDecoratorFunc(flakyDecorator), "in", "out")
+
+	done := runConveyer(t, conv)
+
+	go func() {
+		for range supervisorFailures - 1 {
+			if conv.Send("in", "bad") != nil {
+				return
+			}
+		}
+
+		_ = conv.Send("in", "a")
+	}()
+
+	for restart := 1; restart < supervisorFailures; restart++ {
+		event := <-events
+		require.Equal(t, restart, event.Restarts)
+		require.Positive(t, event.Backoff)
+		require.LessOrEqual(t, event.Backoff, 2*time.Nanosecond)
+	}
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: a", data)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestSupervisorRejectsInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opt  conveyer.SupervisorOption
+		err  error
+	}{
+		{name: "zero backoff", opt: conveyer.WithRestartBackoff(0, time.Second), err: conveyer.ErrInvalidRestartBackoff},
+		{
+			name: "negative backoff",
+			opt:  conveyer.WithRestartBackoff(-time.Second, time.Second),
+			err:  conveyer.ErrInvalidRestartBackoff,
+		},
+		{name: "zero restarts", opt: conveyer.WithRestartBudget(0, time.Minute), err: conveyer.ErrInvalidRestartBudget},
+		{name: "negative restarts", opt: conveyer.WithRestartBudget(-1, time.Minute), err: conveyer.ErrInvalidRestartBudget},
+		{name: "zero window", opt: conveyer.WithRestartBudget(1, 0), err: conveyer.ErrInvalidRestartBudget},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			conv := conveyer.New(supervisorChanSize)
+			conv.Supervise(conveyer.StrategyOneForOne, test.opt)
+			conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+
+			require.ErrorIs(t, conv.Validate(), test.err)
+			require.ErrorIs(t, conv.Start(context.Background()), test.err)
+		})
+	}
+}
+
+func TestAllForOneRestartsEveryHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu     sync.Mutex
+		starts = make(map[string]int)
+	)
+
+	conv := conveyer.New(supervisorChanSize)
+	events := conv.Supervise(conveyer.StrategyAllForOne, conveyer.WithRestartBackoff(supervisorBackoff, supervisorBackoff))
+	conv.Use(func(info conveyer.HandlerInfo, next conveyer.HandlerFunc) conveyer.HandlerFunc {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			starts[info.Name]++
+			mu.Unlock()
+
+			return next(ctx)
+		}
+	})
+	conv.RegisterDecorator(handlers.GenericDecoratorFunc(flakyDecorator), "in", "mid", conveyer.WithName("flaky"))
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "mid", "out", conveyer.WithName("steady"))
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.Send("in", "bad"))
+
+	event := <-events
+	require.Equal(t, "flaky", event.Handler)
+	require.False(t, event.GaveUp)
+
+	require.NoError(t, conv.Send("in", "a"))
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: a", data)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Equal(t, map[string]int{"flaky": 2, "steady": 2}, starts)
+}
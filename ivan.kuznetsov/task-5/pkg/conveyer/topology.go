@@ -0,0 +1,252 @@
+package conveyer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrEmptyInputs              = errors.New("handler has no inputs")
+	ErrEmptyOutputs             = errors.New("handler has no outputs")
+	ErrCycle                    = errors.New("cycle in topology")
+	ErrUnreachableHandler       = errors.New("handler is unreachable from inputs")
+	ErrNoProducer               = errors.New("channel has no producer")
+	ErrNoConsumer               = errors.New("channel has no consumer")
+	ErrConflictingWriters       = errors.New("channel has several writers and no consumer")
+	ErrReplicasRequireDecorator = errors.New("replicas are only supported for decorators")
+)
+
+const (
+	colorWhite = iota
+	colorGray
+	colorBlack
+)
+
+func (k handlerType) String() string {
+	switch k {
+	case hDecorator:
+		return "decorator"
+	case hMultiplexer:
+		return "multiplexer"
+	case hSeparator:
+		return "separator"
+	case hStage:
+		return "stage"
+	default:
+		return "unknown"
+	}
+}
+
+func (h handler[T]) String() string {
+	return fmt.Sprintf("%s[%s -> %s]", h.kind, strings.Join(h.inputIDs, ","), strings.Join(h.outputIDs, ","))
+}
+
+type topology struct {
+	producers map[string][]int
+	consumers map[string][]int
+	channels  []string
+}
+
+func (c *conveyerImpl[T]) DeclareInputs(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, id := range ids {
+		c.ensureChannel(id)
+		c.inputs[id] = struct{}{}
+	}
+}
+
+func (c *conveyerImpl[T]) DeclareOutputs(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, id := range ids {
+		c.ensureChannel(id)
+		c.outputs[id] = struct{}{}
+	}
+}
+
+func (c *conveyerImpl[T]) Validate() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.validate()
+}
+
+func (c *conveyerImpl[T]) validate() error {
+	topo := c.buildTopology()
+
+	errs := c.validateHandlers()
+	errs = append(errs, c.supervisor.validate()...)
+	errs = append(errs, c.validateChannels(topo)...)
+
+	errs = append(errs, c.findUnreachable(topo)...)
+	errs = append(errs, c.findCycles(topo)...)
+
+	return errors.Join(errs...)
+}
+
+func (c *conveyerImpl[T]) buildTopology() topology {
+	topo := topology{
+		producers: make(map[string][]int),
+		consumers: make(map[string][]int),
+		channels:  make([]string, 0, len(c.chans)),
+	}
+
+	for id := range c.chans {
+		topo.channels = append(topo.channels, id)
+	}
+
+	sort.Strings(topo.channels)
+
+	for i, handl := range c.handlers {
+		for _, id := range handl.inputIDs {
+			topo.consumers[id] = append(topo.consumers[id], i)
+		}
+
+		for _, id := range handl.outputIDs {
+			topo.producers[id] = append(topo.producers[id], i)
+		}
+	}
+
+	return topo
+}
+
+func (c *conveyerImpl[T]) strict() bool {
+	return len(c.inputs) > 0 || len(c.outputs) > 0
+}
+
+func (c *conveyerImpl[T]) isInput(topo topology, id string) bool {
+	if _, ok := c.inputs[id]; ok {
+		return true
+	}
+
+	return !c.strict() && len(topo.producers[id]) == 0
+}
+
+func (c *conveyerImpl[T]) validateHandlers() []error {
+	var errs []error
+
+	for _, handl := range c.handlers {
+		if len(handl.inputIDs) == 0 {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrEmptyInputs, handl))
+		}
+
+		if len(handl.outputIDs) == 0 {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrEmptyOutputs, handl))
+		}
+
+		if handl.kind != hDecorator && handl.options.replicas > 1 {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrReplicasRequireDecorator, handl))
+		}
+	}
+
+	return errs
+}
+
+func (c *conveyerImpl[T]) validateChannels(topo topology) []error {
+	var errs []error
+
+	for _, id := range topo.channels {
+		_, declaredInput := c.inputs[id]
+		_, declaredOutput := c.outputs[id]
+		producers, consumers := len(topo.producers[id]), len(topo.consumers[id])
+
+		if producers == 0 && !declaredInput && c.strict() {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrNoProducer, id))
+		}
+
+		if consumers > 0 || declaredOutput || !c.strict() {
+			continue
+		}
+
+		if producers > 1 {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrConflictingWriters, id))
+		} else {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrNoConsumer, id))
+		}
+	}
+
+	return errs
+}
+
+func (c *conveyerImpl[T]) findUnreachable(topo topology) []error {
+	reached := make([]bool, len(c.handlers))
+	queue := make([]string, 0, len(topo.channels))
+
+	for _, id := range topo.channels {
+		if c.isInput(topo, id) {
+			queue = append(queue, id)
+		}
+	}
+
+	visited := make(map[string]bool, len(topo.channels))
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		if visited[id] {
+			continue
+		}
+
+		visited[id] = true
+
+		for _, idx := range topo.consumers[id] {
+			if reached[idx] {
+				continue
+			}
+
+			reached[idx] = true
+
+			queue = append(queue, c.handlers[idx].outputIDs...)
+		}
+	}
+
+	var errs []error
+
+	for i, handl := range c.handlers {
+		if !reached[i] && len(handl.inputIDs) > 0 {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrUnreachableHandler, handl))
+		}
+	}
+
+	return errs
+}
+
+func (c *conveyerImpl[T]) findCycles(topo topology) []error {
+	colors := make([]int, len(c.handlers))
+
+	var (
+		errs  []error
+		visit func(idx int)
+	)
+
+	visit = func(idx int) {
+		colors[idx] = colorGray
+
+		for _, id := range c.handlers[idx].outputIDs {
+			for _, next := range topo.consumers[id] {
+				switch colors[next] {
+				case colorGray:
+					errs = append(errs, fmt.Errorf("%w: %s -> %s", ErrCycle, c.handlers[idx], c.handlers[next]))
+				case colorWhite:
+					visit(next)
+				}
+			}
+		}
+
+		colors[idx] = colorBlack
+	}
+
+	for i := range c.handlers {
+		if colors[i] == colorWhite {
+			visit(i)
+		}
+	}
+
+	return errs
+}
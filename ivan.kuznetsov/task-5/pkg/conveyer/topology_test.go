@@ -0,0 +1,127 @@
+package conveyer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateReportsTypedErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		build func(conv conveyer.Conveyer[string])
+		err   error
+	}{
+		{
+			name: "cycle",
+			build: func(conv conveyer.Conveyer[string]) {
+				conv.RegisterMultiplexer(handlers.MultiplexerFunc, []string{"in", "back"}, "mid")
+				conv.RegisterSeparator(handlers.SeparatorFunc, "mid", []string{"out", "back"})
+			},
+			err: conveyer.ErrCycle,
+		},
+		{
+			name: "unreachable handler",
+			build: func(conv conveyer.Conveyer[string]) {
+				conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+				conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "a", "b")
+				conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "b", "a")
+			},
+			err: conveyer.ErrUnreachableHandler,
+		},
+		{
+			name: "no producer",
+			build: func(conv conveyer.Conveyer[string]) {
+				conv.DeclareInputs("in")
+				conv.DeclareOutputs("out")
+				conv.RegisterMultiplexer(handlers.MultiplexerFunc, []string{"in", "orphan"}, "out")
+			},
+			err: conveyer.ErrNoProducer,
+		},
+		{
+			name: "no consumer",
+			build: func(conv conveyer.Conveyer[string]) {
+				conv.DeclareInputs("in")
+				conv.DeclareOutputs("out")
+				conv.RegisterSeparator(handlers.SeparatorFunc, "in", []string{"out", "lost"})
+			},
+			err: conveyer.ErrNoConsumer,
+		},
+		{
+			name: "conflicting writers",
+			build: func(conv conveyer.Conveyer[string]) {
+				conv.DeclareInputs("a", "b")
+				conv.DeclareOutputs("out")
+				conv.RegisterSeparator(handlers.SeparatorFunc, "a", []string{"out", "lost"})
+				conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "b", "lost")
+			},
+			err: conveyer.ErrConflictingWriters,
+		},
+		{
+			name: "empty inputs",
+			build: func(conv conveyer.Conveyer[string]) {
+				conv.RegisterMultiplexer(handlers.MultiplexerFunc, nil, "out")
+			},
+			err: conveyer.ErrEmptyInputs,
+		},
+		{
+			name: "empty outputs",
+			build: func(conv conveyer.Conveyer[string]) {
+				conv.RegisterSeparator(handlers.SeparatorFunc, "in", nil)
+			},
+			err: conveyer.ErrEmptyOutputs,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			conv := conveyer.New(1)
+			test.build(conv)
+
+			require.ErrorIs(t, conv.Validate(), test.err)
+		})
+	}
+}
+
+func TestValidateAcceptsWellFormedGraph(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(1)
+	conv.DeclareInputs("in-a", "in-b")
+	conv.DeclareOutputs("out-a", "out-b")
+	conv.RegisterMultiplexer(handlers.MultiplexerFunc, []string{"in-a", "in-b"}, "merged")
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "merged", "decorated")
+	conv.RegisterSeparator(handlers.SeparatorFunc, "decorated", []string{"out-a", "out-b"})
+
+	require.NoError(t, conv.Validate())
+}
+
+func TestNonStrictGraphAcceptsSeveralWritersToSink(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(1)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "a", "out")
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "b", "out")
+
+	require.NoError(t, conv.Validate())
+
+	done := runConveyer(t, conv)
+
+	for _, id := range []string{"a", "b"} {
+		require.NoError(t, conv.Send(id, id))
+
+		data, err := conv.Recv("out")
+		require.NoError(t, err)
+		require.Equal(t, "decorated: "+id, data)
+	}
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
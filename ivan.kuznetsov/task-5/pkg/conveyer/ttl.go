@@ -0,0 +1,131 @@
+package conveyer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+var errExpired = errors.New("message expired")
+
+type item[T any] struct {
+	data     T
+	deadline time.Time
+	failure  *failure
+}
+
+type lane struct {
+	deadline atomic.Int64
+}
+
+func WithTTL(ttl time.Duration) ChannelOption {
+	return func(opts *channelOptions) {
+		opts.ttl = ttl
+	}
+}
+
+func WithExpired(id string) ChannelOption {
+	return func(opts *channelOptions) {
+		opts.expired = id
+	}
+}
+
+func (it item[T]) expiredAt(now time.Time) bool {
+	return !it.deadline.IsZero() && !now.Before(it.deadline)
+}
+
+func (ch *channel[T]) stamp(data T, deadline time.Time) item[T] {
+	if ch.ttl > 0 {
+		limit := time.Now().Add(ch.ttl)
+		if deadline.IsZero() || limit.Before(deadline) {
+			deadline = limit
+		}
+	}
+
+	return item[T]{data: data, deadline: deadline, failure: nil}
+}
+
+func (ch *channel[T]) expire(it item[T]) {
+	ch.expired.Add(1)
+
+	if ch.sink != nil {
+		_ = ch.sink.trySend(it.data)
+	}
+}
+
+func newLane() *lane {
+	return &lane{deadline: atomic.Int64{}}
+}
+
+func (l *lane) track(deadline time.Time) {
+	if deadline.IsZero() {
+		l.deadline.Store(0)
+	} else {
+		l.deadline.Store(deadline.UnixNano())
+	}
+}
+
+func (l *lane) inherited() time.Time {
+	deadline := l.deadline.Load()
+	if deadline == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, deadline)
+}
+
+func (c *conveyerImpl[T]) ensureExpired(options channelOptions) *channel[T] {
+	if options.expired == "" {
+		return nil
+	}
+
+	if _, ok := c.expired[options.expired]; !ok {
+		c.expired[options.expired] = newChannel[T](options.capacity)
+	}
+
+	return c.expired[options.expired]
+}
+
+func (c *conveyerImpl[T]) SendTTL(ctx context.Context, id string, data T, ttl time.Duration) error {
+	channel, err := c.lookup(id)
+	if err != nil {
+		return err
+	}
+
+	return channel.deliver(ctx, data, time.Now().Add(ttl))
+}
+
+func (c *conveyerImpl[T]) RecvExpired(id string) (T, error) {
+	c.mu.RLock()
+	channel, ok := c.expired[id]
+	c.mu.RUnlock()
+
+	if !ok {
+		var zero T
+
+		return zero, ErrChanNotFound
+	}
+
+	return channel.recv(context.Background())
+}
+
+func Deadline(ctx context.Context) (time.Time, bool) {
+	policy, ok := policyFrom(ctx)
+	if !ok || policy.lane == nil {
+		return time.Time{}, false
+	}
+
+	deadline := policy.lane.inherited()
+
+	return deadline, !deadline.IsZero()
+}
+
+func Remaining(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := Deadline(ctx)
+	if !ok {
+		return 0, false
+	}
+
+	return time.Until(deadline), true
+}
@@ -0,0 +1,164 @@
+package conveyer_test
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ttlChanSize  = 4
+	shortTTL     = 10 * time.Millisecond
+	longTTL      = time.Minute
+	ttlStaleWait = 3 * shortTTL
+	ttlPoll      = time.Millisecond
+)
+
+func deadlineDecorator(proceed chan struct{}) func(context.Context, chan string, chan string) error {
+	return func(ctx context.Context, input chan string, output chan string) error {
+		for data := range input {
+			<-proceed
+
+			remaining, ok := conveyer.Remaining(ctx)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case output <- fmt.Sprintf("%s:%t", data, ok && remaining > 0 && remaining <= longTTL):
+			}
+		}
+
+		return nil
+	}
+}
+
+func TestChannelTTLRoutesStaleMessagesToExpired(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(ttlChanSize)
+	conv.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+	require.NoError(t, conv.ConfigureChannel("in", conveyer.WithTTL(shortTTL), conveyer.WithExpired("stale")))
+
+	require.NoError(t, conv.Send("in", "old"))
+	time.Sleep(ttlStaleWait)
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.Send("in", "fresh"))
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: fresh", data)
+
+	stale, err := conv.RecvExpired("stale")
+	require.NoError(t, err)
+	require.Equal(t, "old", stale)
+	require.Equal(t, uint64(1), conv.Stats().Channels["in"].Expired)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestMessageDeadlineIsVisibleToHandlers(t *testing.T) {
+	t.Parallel()
+
+	proceed := make(chan struct{})
+
+	conv := conveyer.New(ttlChanSize)
+	conv.RegisterDecorator(deadlineDecorator(proceed), "in", "out", conveyer.WithName("deadline"))
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.SendTTL(context.Background(), "in", "a", longTTL))
+	require.NoError(t, conv.Send("in", "b"))
+
+	for i, expected := range []string{"a:true", "b:false"} {
+		require.Eventually(t, func() bool {
+			return conv.Stats().Handlers["deadline"].Processed == uint64(i+1)
+		}, time.Second, ttlPoll)
+
+		proceed <- struct{}{}
+
+		data, err := conv.Recv("out")
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	}
+
+	require.NoError(t, conv.SendTTL(context.Background(), "out", "stale", shortTTL))
+	time.Sleep(ttlStaleWait)
+
+	_, err := conv.TryRecv("out")
+	require.ErrorIs(t, err, conveyer.ErrChanEmpty)
+	require.Equal(t, uint64(1), conv.Stats().Channels["out"].Expired)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestReplicasSeeDeadlinesOfTheirOwnMessages(t *testing.T) {
+	t.Parallel()
+
+	proceed := make(chan struct{})
+
+	conv := conveyer.New(ttlChanSize)
+	conv.RegisterDecorator(
+		deadlineDecorator(proceed), "in", "out", conveyer.WithName("deadline"), conveyer.WithReplicas(2))
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.SendTTL(context.Background(), "in", "a", longTTL))
+	require.NoError(t, conv.Send("in", "b"))
+
+	require.Eventually(t, func() bool {
+		return conv.Stats().Handlers["deadline"].Processed == 2
+	}, time.Second, ttlPoll)
+
+	received := make([]string, 0, 2)
+
+	for range 2 {
+		proceed <- struct{}{}
+
+		data, err := conv.Recv("out")
+		require.NoError(t, err)
+
+		received = append(received, data)
+	}
+
+	require.ElementsMatch(t, []string{"a:true", "b:false"}, received)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestOutputsDoNotInheritDeadlinesOfOtherInputs(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(ttlChanSize)
+	conv.RegisterMultiplexer(handlers.SortedMergeFunc(cmp.Compare[string]), []string{"b", "a"}, "out")
+	require.NoError(t, conv.ConfigureChannel("a", conveyer.WithTTL(shortTTL)))
+
+	done := runConveyer(t, conv)
+
+	require.NoError(t, conv.Send("b", "x"))
+	require.NoError(t, conv.Send("a", "y"))
+
+	require.Eventually(t, func() bool {
+		return conv.Stats().Channels["out"].Depth == 1
+	}, time.Second, ttlPoll)
+
+	time.Sleep(ttlStaleWait)
+
+	data, err := conv.TryRecv("out")
+	require.NoError(t, err)
+	require.Equal(t, "x", data)
+	require.Zero(t, conv.Stats().Channels["out"].Expired)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
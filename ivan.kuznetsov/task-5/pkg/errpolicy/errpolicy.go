@@ -0,0 +1,103 @@
+package errpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+var ErrPanic = errors.New("handler panicked")
+
+type Policy interface {
+	Backoff(attempt int) (time.Duration, bool)
+	Measure(started time.Time)
+	Fail(ctx context.Context, data any, err error) error
+}
+
+type (
+	policyKey  struct{}
+	recoverKey struct{}
+)
+
+func With(ctx context.Context, policy Policy) context.Context {
+	return context.WithValue(ctx, policyKey{}, policy)
+}
+
+func From(ctx context.Context) (Policy, bool) {
+	policy, ok := ctx.Value(policyKey{}).(Policy)
+
+	return policy, ok
+}
+
+func Apply[T any](ctx context.Context, data T, fn func(T) (T, error)) (T, bool, error) {
+	var zero T
+
+	policy, ok := From(ctx)
+	if !ok {
+		result, err := fn(data)
+		if err != nil {
+			return zero, false, err
+		}
+
+		return result, true, nil
+	}
+
+	defer policy.Measure(time.Now())
+
+	result, err := fn(data)
+
+	for attempt := 0; err != nil; attempt++ {
+		delay, retry := policy.Backoff(attempt)
+		if !retry {
+			break
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return zero, false, policy.Fail(ctx, data, err)
+		case <-timer.C:
+		}
+
+		result, err = fn(data)
+	}
+
+	if err != nil {
+		return zero, false, policy.Fail(ctx, data, err)
+	}
+
+	return result, true, nil
+}
+
+func Report[T any](ctx context.Context, data T, err error) error {
+	policy, ok := From(ctx)
+	if !ok {
+		return err
+	}
+
+	return policy.Fail(ctx, data, err)
+}
+
+func WithRecover(ctx context.Context, handler string) context.Context {
+	return context.WithValue(ctx, recoverKey{}, handler)
+}
+
+func Guard(ctx context.Context, fn func() error) (err error) {
+	handler, ok := ctx.Value(recoverKey{}).(string)
+	if !ok {
+		return fn()
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%w: %s: %v\n%s", ErrPanic, handler, recovered, debug.Stack())
+		}
+	}()
+
+	return fn()
+}
@@ -0,0 +1,98 @@
+package errpolicy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/errpolicy"
+	"github.com/stretchr/testify/require"
+)
+
+var errTransient = errors.New("transient")
+
+type stubPolicy struct {
+	retries  int
+	measured int
+	failed   []any
+}
+
+func (p *stubPolicy) Backoff(attempt int) (time.Duration, bool) {
+	return 0, attempt < p.retries
+}
+
+func (p *stubPolicy) Measure(time.Time) {
+	p.measured++
+}
+
+func (p *stubPolicy) Fail(_ context.Context, data any, _ error) error {
+	p.failed = append(p.failed, data)
+
+	return nil
+}
+
+func failTimes(failures int) func(int) (int, error) {
+	calls := 0
+
+	return func(data int) (int, error) {
+		calls++
+		if calls <= failures {
+			return 0, errTransient
+		}
+
+		return data + 1, nil
+	}
+}
+
+func TestApplyWithoutPolicyReturnsError(t *testing.T) {
+	t.Parallel()
+
+	_, ok, err := errpolicy.Apply(context.Background(), 1, failTimes(1))
+	require.False(t, ok)
+	require.ErrorIs(t, err, errTransient)
+
+	result, ok, err := errpolicy.Apply(context.Background(), 1, failTimes(0))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 2, result)
+}
+
+func TestApplyRetriesThenReportsFailure(t *testing.T) {
+	t.Parallel()
+
+	policy := &stubPolicy{retries: 2, measured: 0, failed: nil}
+	ctx := errpolicy.With(context.Background(), policy)
+
+	result, ok, err := errpolicy.Apply(ctx, 1, failTimes(2))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 2, result)
+
+	_, ok, err = errpolicy.Apply(ctx, 7, failTimes(3))
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, []any{7}, policy.failed)
+	require.Equal(t, 2, policy.measured)
+}
+
+func TestGuardRecoversOnlyWhenEnabled(t *testing.T) {
+	t.Parallel()
+
+	explode := func() error {
+		panic("exploded")
+	}
+
+	require.Panics(t, func() {
+		_ = errpolicy.Guard(context.Background(), explode)
+	})
+
+	ctx := errpolicy.WithRecover(context.Background(), "worker")
+
+	err := errpolicy.Guard(ctx, explode)
+	require.ErrorIs(t, err, errpolicy.ErrPanic)
+	require.ErrorContains(t, err, "worker: exploded")
+	require.ErrorIs(t, errpolicy.Guard(ctx, func() error {
+		return errTransient
+	}), errTransient)
+}
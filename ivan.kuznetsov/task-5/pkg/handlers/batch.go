@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidBatchSize     = errors.New("batch size must be positive")
+	ErrInvalidBatchInterval = errors.New("batch interval must be positive")
+)
+
+func BatchFunc[T any](
+	clock Clock,
+	size int,
+	interval time.Duration,
+	join func([]T) T,
+) (func(context.Context, chan T, chan T) error, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("%w: got %d", ErrInvalidBatchSize, size)
+	}
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: got %s", ErrInvalidBatchInterval, interval)
+	}
+
+	return func(ctx context.Context, input chan T, output chan T) error {
+		batch := make([]T, 0, size)
+
+		timer := clock.NewTimer(interval)
+		timer.Stop()
+
+		defer timer.Stop()
+
+		flush := func() bool {
+			if len(batch) == 0 {
+				return true
+			}
+
+			joined := join(batch)
+			batch = make([]T, 0, size)
+
+			select {
+			case <-ctx.Done():
+				return false
+			case output <- joined:
+				return true
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-timer.C():
+				if !flush() {
+					return nil
+				}
+			case data, ok := <-input:
+				if !ok {
+					flush()
+
+					return nil
+				}
+
+				if len(batch) == 0 {
+					timer.Reset(interval)
+				}
+
+				batch = append(batch, data)
+
+				if len(batch) < size {
+					continue
+				}
+
+				timer.Stop()
+
+				if !flush() {
+					return nil
+				}
+			}
+		}
+	}, nil
+}
+
+func JoinBatchFunc(
+	clock Clock,
+	size int,
+	interval time.Duration,
+	sep string,
+) (func(context.Context, chan string, chan string) error, error) {
+	return BatchFunc(clock, size, interval, func(batch []string) string {
+		return strings.Join(batch, sep)
+	})
+}
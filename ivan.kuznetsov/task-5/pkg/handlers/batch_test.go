@@ -0,0 +1,79 @@
+package handlers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchFuncFlushesBySize(t *testing.T) {
+	t.Parallel()
+
+	output := make(chan string, testChanSize)
+	batch, err := handlers.JoinBatchFunc(handlers.SystemClock(), 2, time.Hour, ",")
+	require.NoError(t, err)
+
+	err = batch(context.Background(), feed("a", "b", "c", "d", "e"), output)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"a,b", "c,d", "e"}, collect(output))
+}
+
+func TestBatchFuncFlushesByTime(t *testing.T) {
+	t.Parallel()
+
+	clock := handlers.NewManualClock(epoch)
+	input := make(chan string)
+	output := make(chan string, testChanSize)
+	batch, err := handlers.JoinBatchFunc(clock, 10, time.Second, ",")
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- batch(context.Background(), input, output)
+	}()
+
+	input <- "a"
+	input <- "b"
+
+	awaitResets(t, clock, 1)
+	clock.Advance(time.Second / 2)
+	require.Empty(t, collect(output))
+
+	clock.Advance(time.Second / 2)
+	require.Equal(t, "a,b", <-output)
+
+	close(input)
+	require.NoError(t, <-done)
+	require.Empty(t, collect(output))
+}
+
+func TestBatchFuncRejectsInvalidSettings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		size     int
+		interval time.Duration
+		err      error
+	}{
+		{name: "zero size", size: 0, interval: time.Hour, err: handlers.ErrInvalidBatchSize},
+		{name: "negative size", size: -1, interval: time.Hour, err: handlers.ErrInvalidBatchSize},
+		{name: "zero interval", size: 1, interval: 0, err: handlers.ErrInvalidBatchInterval},
+		{name: "negative interval", size: 1, interval: -time.Second, err: handlers.ErrInvalidBatchInterval},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			batch, err := handlers.JoinBatchFunc(handlers.SystemClock(), test.size, test.interval, ",")
+			require.ErrorIs(t, err, test.err)
+			require.Nil(t, batch)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import "context"
+
+func BroadcastFunc[T any](ctx context.Context, input chan T, outputs []chan T) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case data, ok := <-input:
+			if !ok {
+				return nil
+			}
+
+			for _, output := range outputs {
+				select {
+				case <-ctx.Done():
+					return nil
+				case output <- data:
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package handlers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastFunc(t *testing.T) {
+	t.Parallel()
+
+	outputs := []chan string{make(chan string, testChanSize), make(chan string, testChanSize)}
+
+	err := handlers.BroadcastFunc(context.Background(), feed("a", "b", "c"), outputs)
+
+	require.NoError(t, err)
+
+	for i, output := range outputs {
+		require.Equal(t, []string{"a", "b", "c"}, collect(output), "output %d", i)
+	}
+}
+
+func TestBroadcastFuncStopsOnCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	blocked := []chan string{make(chan string)}
+
+	require.NoError(t, handlers.BroadcastFunc(ctx, make(chan string), blocked))
+}
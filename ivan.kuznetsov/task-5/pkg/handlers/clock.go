@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+	NewTimer(delay time.Duration) Timer
+}
+
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(delay time.Duration) bool
+}
+
+type systemClock struct{}
+
+type systemTimer struct {
+	timer *time.Timer
+}
+
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	resets int
+	timers []*manualTimer
+}
+
+type manualTimer struct {
+	clock    *ManualClock
+	ch       chan time.Time
+	deadline time.Time
+	active   bool
+}
+
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(delay time.Duration) Timer {
+	return systemTimer{timer: time.NewTimer(delay)}
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t systemTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+func (t systemTimer) Reset(delay time.Duration) bool {
+	return t.timer.Reset(delay)
+}
+
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{
+		mu:     sync.Mutex{},
+		now:    start,
+		resets: 0,
+		timers: nil,
+	}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *ManualClock) NewTimer(delay time.Duration) Timer {
+	timer := &manualTimer{
+		clock:    c,
+		ch:       make(chan time.Time, 1),
+		deadline: time.Time{},
+		active:   false,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.timers = append(c.timers, timer)
+	timer.arm(delay)
+
+	return timer
+}
+
+func (c *ManualClock) Advance(delta time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(delta)
+	c.fire()
+}
+
+func (c *ManualClock) Resets() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.resets
+}
+
+func (c *ManualClock) fire() {
+	for _, timer := range c.timers {
+		if !timer.active || timer.deadline.After(c.now) {
+			continue
+		}
+
+		timer.active = false
+
+		select {
+		case timer.ch <- c.now:
+		default:
+		}
+	}
+}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	wasActive := t.active
+	t.active = false
+	t.drain()
+
+	return wasActive
+}
+
+func (t *manualTimer) Reset(delay time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	wasActive := t.active
+	t.drain()
+	t.clock.resets++
+	t.arm(delay)
+
+	return wasActive
+}
+
+func (t *manualTimer) arm(delay time.Duration) {
+	t.deadline = t.clock.now.Add(delay)
+	t.active = true
+	t.clock.fire()
+}
+
+func (t *manualTimer) drain() {
+	select {
+	case <-t.ch:
+	default:
+	}
+}
@@ -4,33 +4,56 @@ import (
 	"context"
 	"errors"
 	"strings"
+
+	"github.com/kuzid-17/task-5/pkg/errpolicy"
 )
 
 var ErrCantBeDecorated = errors.New("can't be decorated")
 
-func PrefixDecoratorFunc(ctx context.Context, input chan string, output chan string) error {
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case data, ok := <-input:
-			if !ok {
+func GenericDecoratorFunc[T any](
+	decorate func(T) (T, error),
+) func(context.Context, chan T, chan T) error {
+	return func(ctx context.Context, input chan T, output chan T) error {
+		for {
+			select {
+			case <-ctx.Done():
 				return nil
-			}
+			case data, ok := <-input:
+				if !ok {
+					return nil
+				}
 
-			if strings.Contains(data, "no decorator") {
-				return ErrCantBeDecorated
-			}
+				data, ok, err := errpolicy.Apply(ctx, data, decorate)
+				if err != nil {
+					return err
+				}
 
-			if !strings.HasPrefix(data, "decorated: ") {
-				data = "decorated: " + data
-			}
+				if !ok {
+					continue
+				}
 
-			select {
-			case <-ctx.Done():
-				return nil
-			case output <- data:
+				select {
+				case <-ctx.Done():
+					return nil
+				case output <- data:
+				}
 			}
 		}
 	}
 }
+
+func PrefixDecoratorFunc(ctx context.Context, input chan string, output chan string) error {
+	return GenericDecoratorFunc(prefixDecorate)(ctx, input, output)
+}
+
+func prefixDecorate(data string) (string, error) {
+	if strings.Contains(data, "no decorator") {
+		return "", ErrCantBeDecorated
+	}
+
+	if !strings.HasPrefix(data, "decorated: ") {
+		data = "decorated: " + data
+	}
+
+	return data, nil
+}
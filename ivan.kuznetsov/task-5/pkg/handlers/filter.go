@@ -0,0 +1,28 @@
+package handlers
+
+import "context"
+
+func FilterFunc[T any](keep func(T) bool) func(context.Context, chan T, chan T) error {
+	return func(ctx context.Context, input chan T, output chan T) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case data, ok := <-input:
+				if !ok {
+					return nil
+				}
+
+				if !keep(data) {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case output <- data:
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package handlers_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const testChanSize = 16
+
+func feed[T any](values ...T) chan T {
+	input := make(chan T, len(values))
+
+	for _, value := range values {
+		input <- value
+	}
+
+	close(input)
+
+	return input
+}
+
+func collect[T any](output chan T) []T {
+	var values []T
+
+	for {
+		select {
+		case value := <-output:
+			values = append(values, value)
+		default:
+			return values
+		}
+	}
+}
+
+func TestFilterFunc(t *testing.T) {
+	t.Parallel()
+
+	output := make(chan string, testChanSize)
+	filter := handlers.FilterFunc(func(data string) bool {
+		return !strings.HasPrefix(data, "skip")
+	})
+
+	err := filter(context.Background(), feed("a", "skip b", "c", "skip d"), output)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "c"}, collect(output))
+}
+
+func TestRegisterFilter(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(testChanSize)
+	handlers.RegisterFilter(conv, func(data string) bool { return data != "drop" }, "in", "out")
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conv.Run(context.Background())
+	}()
+
+	for _, data := range []string{"keep", "drop", "keep too"} {
+		require.NoError(t, conv.Send("in", data))
+	}
+
+	for _, expected := range []string{"keep", "keep too"} {
+		data, err := conv.Recv("out")
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	}
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
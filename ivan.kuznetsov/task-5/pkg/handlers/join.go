@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidWindow = errors.New("join window size must be positive")
+	ErrJoinArity     = errors.New("join needs two inputs and at least one output")
+)
+
+const (
+	joinLeft = iota
+	joinRight
+	joinSides
+)
+
+const (
+	joinMatched = iota
+	joinUnmatched
+)
+
+type Window struct {
+	Size    time.Duration
+	Sliding bool
+}
+
+type joinEntry[T any, K comparable] struct {
+	data    T
+	key     K
+	side    int
+	expires time.Time
+	matched bool
+}
+
+type joinWindow[T any, K comparable] struct {
+	window  Window
+	order   []*joinEntry[T, K]
+	waiting [joinSides]map[K][]*joinEntry[T, K]
+}
+
+func TumblingWindow(size time.Duration) Window {
+	return Window{Size: size, Sliding: false}
+}
+
+func SlidingWindow(size time.Duration) Window {
+	return Window{Size: size, Sliding: true}
+}
+
+func (w Window) expiry(now time.Time) time.Time {
+	if w.Sliding {
+		return now.Add(w.Size)
+	}
+
+	return now.Truncate(w.Size).Add(w.Size)
+}
+
+func newJoinWindow[T any, K comparable](window Window) *joinWindow[T, K] {
+	return &joinWindow[T, K]{
+		window:  window,
+		order:   nil,
+		waiting: [joinSides]map[K][]*joinEntry[T, K]{make(map[K][]*joinEntry[T, K]), make(map[K][]*joinEntry[T, K])},
+	}
+}
+
+func (w *joinWindow[T, K]) match(side int, key K) (*joinEntry[T, K], bool) {
+	other := w.waiting[1-side]
+
+	candidates := other[key]
+	if len(candidates) == 0 {
+		return nil, false
+	}
+
+	partner := candidates[0]
+	partner.matched = true
+
+	if len(candidates) == 1 {
+		delete(other, key)
+	} else {
+		other[key] = candidates[1:]
+	}
+
+	return partner, true
+}
+
+func (w *joinWindow[T, K]) add(data T, key K, side int, now time.Time) {
+	entry := &joinEntry[T, K]{
+		data:    data,
+		key:     key,
+		side:    side,
+		expires: w.window.expiry(now),
+		matched: false,
+	}
+
+	w.order = append(w.order, entry)
+	w.waiting[side][key] = append(w.waiting[side][key], entry)
+}
+
+func (w *joinWindow[T, K]) expire(now time.Time, all bool) []T {
+	var unmatched []T
+
+	for len(w.order) > 0 && (all || !w.order[0].expires.After(now)) {
+		entry := w.order[0]
+		w.order = w.order[1:]
+
+		if entry.matched {
+			continue
+		}
+
+		waiting := w.waiting[entry.side]
+		if len(waiting[entry.key]) == 1 {
+			delete(waiting, entry.key)
+		} else {
+			waiting[entry.key] = waiting[entry.key][1:]
+		}
+
+		unmatched = append(unmatched, entry.data)
+	}
+
+	return unmatched
+}
+
+func (w *joinWindow[T, K]) next() (time.Time, bool) {
+	for len(w.order) > 0 && w.order[0].matched {
+		w.order = w.order[1:]
+	}
+
+	if len(w.order) == 0 {
+		return time.Time{}, false
+	}
+
+	return w.order[0].expires, true
+}
+
+func JoinFunc[T any, K comparable](
+	clock Clock,
+	window Window,
+	leftKey func(T) K,
+	rightKey func(T) K,
+	combine func(left T, right T) T,
+) func(context.Context, []chan T, []chan T) error {
+	keys := [joinSides]func(T) K{leftKey, rightKey}
+
+	return func(ctx context.Context, inputs []chan T, outputs []chan T) error {
+		if window.Size <= 0 {
+			return fmt.Errorf("%w: got %s", ErrInvalidWindow, window.Size)
+		}
+
+		if len(inputs) != joinSides || len(outputs) == 0 {
+			return fmt.Errorf("%w: got %d inputs and %d outputs", ErrJoinArity, len(inputs), len(outputs))
+		}
+
+		pending := newJoinWindow[T, K](window)
+		sources := [joinSides]chan T{inputs[joinLeft], inputs[joinRight]}
+
+		timer := clock.NewTimer(window.Size)
+		timer.Stop()
+
+		defer timer.Stop()
+
+		emit := func(output chan T, values ...T) bool {
+			for _, value := range values {
+				select {
+				case <-ctx.Done():
+					return false
+				case output <- value:
+				}
+			}
+
+			return true
+		}
+
+		unmatched := func(values []T) bool {
+			if len(outputs) <= joinUnmatched {
+				return true
+			}
+
+			return emit(outputs[joinUnmatched], values...)
+		}
+
+		var armed time.Time
+
+		for sources[joinLeft] != nil || sources[joinRight] != nil {
+			if expires, ok := pending.next(); ok && !expires.Equal(armed) {
+				armed = expires
+				timer.Reset(expires.Sub(clock.Now()))
+			}
+
+			var (
+				data T
+				side int
+				ok   bool
+			)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-timer.C():
+				armed = time.Time{}
+
+				if !unmatched(pending.expire(clock.Now(), false)) {
+					return nil
+				}
+
+				continue
+			case data, ok = <-sources[joinLeft]:
+				side = joinLeft
+			case data, ok = <-sources[joinRight]:
+				side = joinRight
+			}
+
+			if !ok {
+				sources[side] = nil
+
+				continue
+			}
+
+			now := clock.Now()
+			if !unmatched(pending.expire(now, false)) {
+				return nil
+			}
+
+			key := keys[side](data)
+
+			partner, found := pending.match(side, key)
+			if !found {
+				pending.add(data, key, side, now)
+
+				continue
+			}
+
+			left, right := partner.data, data
+			if side == joinLeft {
+				left, right = data, partner.data
+			}
+
+			if !emit(outputs[joinMatched], combine(left, right)) {
+				return nil
+			}
+		}
+
+		unmatched(pending.expire(clock.Now(), true))
+
+		return nil
+	}
+}
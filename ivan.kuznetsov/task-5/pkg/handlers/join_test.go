@@ -0,0 +1,205 @@
+package handlers_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	joinWindowSize = time.Second
+	joinBeforeEdge = 900 * time.Millisecond
+	joinAcrossEdge = 200 * time.Millisecond
+)
+
+func joinKey(data string) string {
+	key, _, _ := strings.Cut(data, "=")
+
+	return key
+}
+
+func joinPair(left string, right string) string {
+	return left + "|" + right
+}
+
+type joinRun struct {
+	left    chan string
+	right   chan string
+	matched chan string
+	side    chan string
+	done    chan error
+}
+
+func startJoin(clock handlers.Clock, window handlers.Window) joinRun {
+	run := joinRun{
+		left:    make(chan string),
+		right:   make(chan string),
+		matched: make(chan string, testChanSize),
+		side:    make(chan string, testChanSize),
+		done:    make(chan error, 1),
+	}
+
+	join := handlers.JoinFunc(clock, window, joinKey, joinKey, joinPair)
+
+	go func() {
+		run.done <- join(context.Background(), []chan string{run.left, run.right}, []chan string{run.matched, run.side})
+	}()
+
+	return run
+}
+
+func (r joinRun) finish(t *testing.T) {
+	t.Helper()
+
+	close(r.left)
+	close(r.right)
+	require.NoError(t, <-r.done)
+}
+
+func TestJoinFuncMatchesWithinTumblingWindow(t *testing.T) {
+	t.Parallel()
+
+	clock := handlers.NewManualClock(epoch)
+	run := startJoin(clock, handlers.TumblingWindow(joinWindowSize))
+
+	run.left <- "a=order"
+	run.right <- "a=payment"
+	require.Equal(t, "a=order|a=payment", <-run.matched)
+
+	run.left <- "b=order"
+	run.right <- "c=payment"
+	run.left <- "c=order"
+	require.Equal(t, "c=order|c=payment", <-run.matched)
+
+	clock.Advance(joinWindowSize)
+	require.Equal(t, "b=order", <-run.side)
+
+	run.finish(t)
+	require.Empty(t, collect(run.matched))
+	require.Empty(t, collect(run.side))
+}
+
+func TestJoinFuncWindowEdges(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		window  handlers.Window
+		matched []string
+		side    []string
+	}{
+		{
+			name:    "tumbling window splits at the boundary",
+			window:  handlers.TumblingWindow(joinWindowSize),
+			matched: nil,
+			side:    []string{"k=order", "k=payment"},
+		},
+		{
+			name:    "sliding window spans the boundary",
+			window:  handlers.SlidingWindow(joinWindowSize),
+			matched: []string{"k=order|k=payment"},
+			side:    nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := handlers.NewManualClock(epoch)
+			run := startJoin(clock, test.window)
+
+			clock.Advance(joinBeforeEdge)
+			run.left <- "k=order"
+			awaitResets(t, clock, 1)
+
+			clock.Advance(joinAcrossEdge)
+			run.right <- "k=payment"
+
+			run.finish(t)
+			require.Equal(t, test.matched, collect(run.matched))
+			require.Equal(t, test.side, collect(run.side))
+		})
+	}
+}
+
+func TestJoinFuncRejectsInvalidSettings(t *testing.T) {
+	t.Parallel()
+
+	left, right, matched := make(chan string), make(chan string), make(chan string)
+
+	tests := []struct {
+		name    string
+		window  handlers.Window
+		inputs  []chan string
+		outputs []chan string
+		err     error
+	}{
+		{
+			name:    "zero window",
+			window:  handlers.Window{Size: 0, Sliding: false},
+			inputs:  []chan string{left, right},
+			outputs: []chan string{matched},
+			err:     handlers.ErrInvalidWindow,
+		},
+		{
+			name:    "one input",
+			window:  handlers.TumblingWindow(joinWindowSize),
+			inputs:  []chan string{left},
+			outputs: []chan string{matched},
+			err:     handlers.ErrJoinArity,
+		},
+		{
+			name:    "no outputs",
+			window:  handlers.TumblingWindow(joinWindowSize),
+			inputs:  []chan string{left, right},
+			outputs: nil,
+			err:     handlers.ErrJoinArity,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			join := handlers.JoinFunc(handlers.NewManualClock(epoch), test.window, joinKey, joinKey, joinPair)
+			require.ErrorIs(t, join(context.Background(), test.inputs, test.outputs), test.err)
+		})
+	}
+}
+
+func TestRegisterJoin(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(testChanSize)
+	handlers.RegisterJoin(conv, handlers.SystemClock(), handlers.SlidingWindow(time.Minute),
+		joinKey, joinKey, joinPair, [2]string{"orders", "payments"}, []string{"out", "unmatched"})
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conv.Run(context.Background())
+	}()
+
+	require.NoError(t, conv.Send("payments", "a=payment"))
+	require.NoError(t, conv.Send("orders", "b=order"))
+	require.NoError(t, conv.Send("orders", "a=order"))
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "a=order|a=payment", data)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+
+	data, err = conv.Recv("unmatched")
+	require.NoError(t, err)
+	require.Equal(t, "b=order", data)
+
+	require.Equal(t, "stage", conv.Stats().Handlers["stage[orders,payments -> out,unmatched]"].Kind)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+)
+
+const (
+	mixShift       = 33
+	mixMultiplier1 = 0xff51afd7ed558ccd
+	mixMultiplier2 = 0xc4ceb9fe1a85ec53
+)
+
+func KeySeparatorFunc[T any](key func(T) string) func(context.Context, chan T, []chan T) error {
+	return func(ctx context.Context, input chan T, outputs []chan T) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case data, ok := <-input:
+				if !ok {
+					return nil
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case outputs[RouteKey(key(data), len(outputs))] <- data:
+				}
+			}
+		}
+	}
+}
+
+func RouteKey(key string, outputs int) int {
+	best, bestWeight := 0, uint64(0)
+
+	for i := range outputs {
+		hasher := fnv.New64a()
+
+		_, _ = hasher.Write([]byte(key))
+		_, _ = hasher.Write(binary.LittleEndian.AppendUint64(nil, uint64(i)))
+
+		if weight := mix(hasher.Sum64()); i == 0 || weight > bestWeight {
+			best, bestWeight = i, weight
+		}
+	}
+
+	return best
+}
+
+func mix(hash uint64) uint64 {
+	hash ^= hash >> mixShift
+	hash *= mixMultiplier1
+	hash ^= hash >> mixShift
+	hash *= mixMultiplier2
+	hash ^= hash >> mixShift
+
+	return hash
+}
@@ -0,0 +1,79 @@
+package handlers_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const routedKeys = 10000
+
+func userKey(data string) string {
+	key, _, _ := strings.Cut(data, ":")
+
+	return key
+}
+
+func TestKeySeparatorFuncIsSticky(t *testing.T) {
+	t.Parallel()
+
+	outputs := []chan string{
+		make(chan string, testChanSize), make(chan string, testChanSize), make(chan string, testChanSize),
+	}
+
+	err := handlers.KeySeparatorFunc(userKey)(
+		context.Background(),
+		feed("alice:1", "bob:1", "alice:2", "carol:1", "bob:2", "alice:3"),
+		outputs,
+	)
+	require.NoError(t, err)
+
+	seen := make(map[string]int)
+
+	for i, output := range outputs {
+		for _, data := range collect(output) {
+			key := userKey(data)
+			if previous, ok := seen[key]; ok {
+				require.Equal(t, previous, i, "key %s was routed to several outputs", key)
+			}
+
+			seen[key] = i
+		}
+	}
+
+	require.Len(t, seen, 3)
+}
+
+func TestRouteKeyStableWhenOutputsAdded(t *testing.T) {
+	t.Parallel()
+
+	const outputs = 4
+
+	moved := 0
+	counts := make([]int, outputs+1)
+
+	for i := range routedKeys {
+		key := "key-" + strconv.Itoa(i)
+
+		before, after := handlers.RouteKey(key, outputs), handlers.RouteKey(key, outputs+1)
+		counts[after]++
+
+		if before != after {
+			require.Equal(t, outputs, after, "key %s moved between old outputs", key)
+
+			moved++
+		}
+	}
+
+	expected := routedKeys / (outputs + 1)
+
+	require.InDelta(t, expected, moved, float64(expected)/5, "unexpected share of moved keys")
+
+	for i, count := range counts {
+		require.InDelta(t, expected, count, float64(expected)/5, "output %d is unbalanced", i)
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import "context"
+
+func SortedMergeFunc[T any](compare func(T, T) int) func(context.Context, []chan T, chan T) error {
+	return func(ctx context.Context, inputs []chan T, output chan T) error {
+		heads := make([]T, len(inputs))
+		filled := make([]bool, len(inputs))
+		closed := make([]bool, len(inputs))
+
+		for {
+			for i, input := range inputs {
+				if filled[i] || closed[i] {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case data, ok := <-input:
+					heads[i], filled[i], closed[i] = data, ok, !ok
+				}
+			}
+
+			smallest := -1
+
+			for i := range heads {
+				if filled[i] && (smallest < 0 || compare(heads[i], heads[smallest]) < 0) {
+					smallest = i
+				}
+			}
+
+			if smallest < 0 {
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case output <- heads[smallest]:
+				filled[smallest] = false
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package handlers_test
+
+import (
+	"cmp"
+	"context"
+	"testing"
+
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortedMergeFunc(t *testing.T) {
+	t.Parallel()
+
+	inputs := []chan int{feed(1, 4, 7), feed(2, 5, 8, 9), feed[int](), feed(3, 6)}
+	output := make(chan int, testChanSize)
+
+	err := handlers.SortedMergeFunc(cmp.Compare[int])(context.Background(), inputs, output)
+
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, collect(output))
+}
+
+func TestSortedMergeFuncWaitsForAllInputs(t *testing.T) {
+	t.Parallel()
+
+	late := make(chan string)
+	inputs := []chan string{feed("b", "c"), late}
+	output := make(chan string, testChanSize)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- handlers.SortedMergeFunc(cmp.Compare[string])(context.Background(), inputs, output)
+	}()
+
+	late <- "a"
+	close(late)
+
+	require.NoError(t, <-done)
+	require.Equal(t, []string{"a", "b", "c"}, collect(output))
+}
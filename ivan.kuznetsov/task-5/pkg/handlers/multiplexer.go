@@ -3,42 +3,58 @@ package handlers
 import (
 	"context"
 	"strings"
-	"sync"
+
+	"github.com/kuzid-17/task-5/pkg/errpolicy"
+	"golang.org/x/sync/errgroup"
 )
 
-func MultiplexerFunc(ctx context.Context, inputs []chan string, output chan string) error {
-	var inputsWg sync.WaitGroup
-
-	for _, input := range inputs {
-		inputsWg.Add(1)
-
-		go func(inChan chan string) {
-			defer inputsWg.Done()
-
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case data, ok := <-inChan:
-					if !ok {
-						return
-					}
-
-					if strings.Contains(data, "no multiplexer") {
-						continue
-					}
-
-					select {
-					case <-ctx.Done():
-						return
-					case output <- data:
-					}
-				}
+func GenericMultiplexerFunc[T any](
+	skip func(T) bool,
+) func(context.Context, []chan T, chan T) error {
+	return func(ctx context.Context, inputs []chan T, output chan T) error {
+		group, groupCtx := errgroup.WithContext(ctx)
+
+		for _, input := range inputs {
+			group.Go(func() error {
+				return errpolicy.Guard(groupCtx, func() error {
+					forward(groupCtx, input, output, skip)
+
+					return nil
+				})
+			})
+		}
+
+		return group.Wait()
+	}
+}
+
+func forward[T any](ctx context.Context, input chan T, output chan T, skip func(T) bool) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case data, ok := <-input:
+			if !ok {
+				return
 			}
-		}(input)
+
+			if skip != nil && skip(data) {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output <- data:
+			}
+		}
 	}
+}
 
-	inputsWg.Wait()
+func MultiplexerFunc(ctx context.Context, inputs []chan string, output chan string) error {
+	return GenericMultiplexerFunc(skipNoMultiplexer)(ctx, inputs, output)
+}
 
-	return nil
+func skipNoMultiplexer(data string) bool {
+	return strings.Contains(data, "no multiplexer")
 }
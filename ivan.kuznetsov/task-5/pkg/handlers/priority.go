@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+)
+
+const strideScale = 1 << 20
+
+var ErrPriorityCount = errors.New("priorities must match the number of inputs")
+
+type PriorityOption func(*priorityConfig)
+
+type priorityConfig struct {
+	priorities []int
+	fair       bool
+}
+
+type prioritySource[T any] struct {
+	input    chan T
+	priority int
+	stride   int
+	pass     int
+	head     T
+	ready    bool
+}
+
+func WithPriorities(priorities ...int) PriorityOption {
+	return func(config *priorityConfig) {
+		config.priorities = priorities
+	}
+}
+
+func WithFairShare() PriorityOption {
+	return func(config *priorityConfig) {
+		config.fair = true
+	}
+}
+
+func PriorityMultiplexerFunc[T any](opts ...PriorityOption) func(context.Context, []chan T, chan T) error {
+	config := priorityConfig{
+		priorities: nil,
+		fair:       false,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return func(ctx context.Context, inputs []chan T, output chan T) error {
+		sources, err := newPrioritySources(ctx, config, inputs)
+		if err != nil {
+			return err
+		}
+
+		cases := waitCases(ctx, sources)
+
+		for {
+			if !fill(sources, cases) {
+				return nil
+			}
+
+			chosen := pickPriority(sources, config.fair)
+			if chosen == nil {
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case output <- chosen.head:
+			}
+
+			var zero T
+
+			chosen.head, chosen.ready = zero, false
+		}
+	}
+}
+
+func newPrioritySources[T any](
+	ctx context.Context,
+	config priorityConfig,
+	inputs []chan T,
+) ([]*prioritySource[T], error) {
+	priorities := config.priorities
+	if priorities == nil {
+		priorities = conveyer.InputPriorities(ctx)
+	} else if len(priorities) != len(inputs) {
+		return nil, fmt.Errorf("%w: got %d priorities for %d inputs", ErrPriorityCount, len(priorities), len(inputs))
+	}
+
+	sources := make([]*prioritySource[T], len(inputs))
+
+	for i, input := range inputs {
+		priority := len(inputs) - i
+		if len(priorities) == len(inputs) {
+			priority = priorities[i]
+		}
+
+		sources[i] = &prioritySource[T]{
+			input:    input,
+			priority: priority,
+			stride:   strideScale / max(priority, 1),
+			pass:     0,
+			head:     *new(T),
+			ready:    false,
+		}
+	}
+
+	return sources, nil
+}
+
+func waitCases[T any](ctx context.Context, sources []*prioritySource[T]) []reflect.SelectCase {
+	cases := make([]reflect.SelectCase, 0, len(sources)+1)
+	cases = append(cases, recvCase(ctx.Done()))
+
+	for _, source := range sources {
+		cases = append(cases, recvCase(source.input))
+	}
+
+	return cases
+}
+
+func fill[T any](sources []*prioritySource[T], cases []reflect.SelectCase) bool {
+	waiting := false
+
+	for _, source := range sources {
+		if source.ready || source.input == nil {
+			waiting = waiting || source.ready
+
+			continue
+		}
+
+		select {
+		case data, ok := <-source.input:
+			if !ok {
+				source.input = nil
+
+				continue
+			}
+
+			source.head, source.ready = data, true
+			waiting = true
+		default:
+		}
+	}
+
+	if waiting {
+		return true
+	}
+
+	return awaitAny(sources, cases)
+}
+
+func awaitAny[T any](sources []*prioritySource[T], cases []reflect.SelectCase) bool {
+	open := 0
+
+	for i, source := range sources {
+		if source.input == nil {
+			cases[i+1].Chan = reflect.Value{}
+		} else {
+			open++
+		}
+	}
+
+	for open > 0 {
+		chosen, value, ok := reflect.Select(cases)
+		if chosen == 0 {
+			return false
+		}
+
+		source := sources[chosen-1]
+
+		if !ok {
+			source.input = nil
+			cases[chosen].Chan = reflect.Value{}
+			open--
+
+			continue
+		}
+
+		data, _ := value.Interface().(T)
+		source.head, source.ready = data, true
+
+		return true
+	}
+
+	return true
+}
+
+func recvCase[C any](ch <-chan C) reflect.SelectCase {
+	return reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch), Send: reflect.Value{}}
+}
+
+func pickPriority[T any](sources []*prioritySource[T], fair bool) *prioritySource[T] {
+	var chosen *prioritySource[T]
+
+	for _, source := range sources {
+		if !source.ready {
+			continue
+		}
+
+		switch {
+		case chosen == nil:
+			chosen = source
+		case fair && source.pass < chosen.pass:
+			chosen = source
+		case !fair && source.priority > chosen.priority:
+			chosen = source
+		}
+	}
+
+	if chosen != nil && fair {
+		floor := chosen.pass
+
+		for _, source := range sources {
+			if !source.ready && source.pass < floor {
+				source.pass = floor
+			}
+		}
+
+		chosen.pass += chosen.stride
+	}
+
+	return chosen
+}
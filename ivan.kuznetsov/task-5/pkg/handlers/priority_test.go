@@ -0,0 +1,184 @@
+package handlers_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	priorityBacklog = 40
+	priorityWindow  = 40
+	urgentPriority  = 3
+	bulkPriority    = 1
+)
+
+func labelled(label string, count int) chan string {
+	values := make([]string, count)
+
+	for i := range values {
+		values[i] = fmt.Sprintf("%s%d", label, i)
+	}
+
+	return feed(values...)
+}
+
+func countLabel(values []string, label string) int {
+	count := 0
+
+	for _, value := range values {
+		if strings.HasPrefix(value, label) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestPriorityMultiplexerFuncDrainsHigherPriorityFirst(t *testing.T) {
+	t.Parallel()
+
+	inputs := []chan string{feed("b0", "b1", "b2"), feed("u0", "u1")}
+	output := make(chan string, testChanSize)
+
+	mux := handlers.PriorityMultiplexerFunc[string](handlers.WithPriorities(bulkPriority, urgentPriority))
+
+	require.NoError(t, mux(context.Background(), inputs, output))
+	require.Equal(t, []string{"u0", "u1", "b0", "b1", "b2"}, collect(output))
+}
+
+func TestPriorityMultiplexerFuncDefaultsToInputOrder(t *testing.T) {
+	t.Parallel()
+
+	inputs := []chan string{feed("a0", "a1"), feed("b0"), feed("c0")}
+	output := make(chan string, testChanSize)
+
+	require.NoError(t, handlers.PriorityMultiplexerFunc[string]()(context.Background(), inputs, output))
+	require.Equal(t, []string{"a0", "a1", "b0", "c0"}, collect(output))
+}
+
+func TestPriorityMultiplexerFuncRejectsMismatchedPriorities(t *testing.T) {
+	t.Parallel()
+
+	inputs := []chan string{feed("a0"), feed("b0"), feed("c0")}
+	output := make(chan string, testChanSize)
+
+	mux := handlers.PriorityMultiplexerFunc[string](handlers.WithPriorities(bulkPriority, urgentPriority))
+
+	require.ErrorIs(t, mux(context.Background(), inputs, output), handlers.ErrPriorityCount)
+	require.Empty(t, collect(output))
+}
+
+func TestPriorityMultiplexerFuncPreemptsBulkUnderContention(t *testing.T) {
+	t.Parallel()
+
+	bulk := labelled("b", priorityBacklog)
+	urgent := make(chan string, testChanSize)
+	output := make(chan string)
+
+	mux := handlers.PriorityMultiplexerFunc[string](handlers.WithPriorities(bulkPriority, urgentPriority))
+	done := make(chan error, 1)
+
+	go func() {
+		done <- mux(context.Background(), []chan string{bulk, urgent}, output)
+	}()
+
+	require.Equal(t, "b0", <-output)
+
+	urgent <- "u0"
+	urgent <- "u1"
+	close(urgent)
+
+	next := []string{<-output, <-output, <-output}
+	at := slices.Index(next, "u0")
+
+	require.LessOrEqual(t, at, 1, "at most one in-flight bulk message may precede urgent ones: %v", next)
+	require.Equal(t, "u1", next[at+1])
+
+	for range priorityBacklog - len(next) + 1 {
+		require.True(t, strings.HasPrefix(<-output, "b"))
+	}
+
+	require.NoError(t, <-done)
+}
+
+func TestPriorityMultiplexerFuncShares(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		opts   []handlers.PriorityOption
+		urgent int
+	}{
+		{
+			name:   "strict priority starves the bulk input",
+			opts:   []handlers.PriorityOption{handlers.WithPriorities(urgentPriority, bulkPriority)},
+			urgent: priorityWindow,
+		},
+		{
+			name: "fair share follows the weights",
+			opts: []handlers.PriorityOption{
+				handlers.WithPriorities(urgentPriority, bulkPriority),
+				handlers.WithFairShare(),
+			},
+			urgent: priorityWindow * urgentPriority / (urgentPriority + bulkPriority),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			inputs := []chan string{labelled("u", priorityBacklog), labelled("b", priorityBacklog)}
+			output := make(chan string, 2*priorityBacklog)
+
+			require.NoError(t, handlers.PriorityMultiplexerFunc[string](test.opts...)(context.Background(), inputs, output))
+
+			values := collect(output)
+			require.Len(t, values, 2*priorityBacklog)
+			require.InDelta(t, test.urgent, countLabel(values[:priorityWindow], "u"), 1)
+		})
+	}
+}
+
+func TestPriorityMultiplexerReadsChannelPriorities(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(testChanSize)
+	require.NoError(t, conv.ConfigureChannel("bulk", conveyer.WithPriority(bulkPriority)))
+	require.NoError(t, conv.ConfigureChannel("urgent", conveyer.WithPriority(urgentPriority)))
+
+	conv.RegisterMultiplexer(func(ctx context.Context, inputs []chan string, output chan string) error {
+		select {
+		case <-ctx.Done():
+		case output <- fmt.Sprint(conveyer.InputPriorities(ctx)):
+		}
+
+		return handlers.PriorityMultiplexerFunc[string]()(ctx, inputs, output)
+	}, []string{"bulk", "urgent", "plain"}, "out")
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conv.Run(context.Background())
+	}()
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "[1 3 0]", data)
+
+	require.NoError(t, conv.Send("urgent", "u0"))
+
+	data, err = conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "u0", data)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidRate     = errors.New("rate limit interval must be positive")
+	ErrInvalidBurst    = errors.New("rate limit burst must be positive")
+	ErrInvalidThrottle = errors.New("throttle interval must be positive")
+	ErrInvalidDebounce = errors.New("debounce quiet period must be positive")
+)
+
+type tokenBucket struct {
+	every  time.Duration
+	burst  int
+	tokens int
+	last   time.Time
+}
+
+func newTokenBucket(now time.Time, every time.Duration, burst int) *tokenBucket {
+	return &tokenBucket{
+		every:  every,
+		burst:  burst,
+		tokens: burst,
+		last:   now,
+	}
+}
+
+func (b *tokenBucket) take(now time.Time) time.Duration {
+	if elapsed := now.Sub(b.last); elapsed >= b.every {
+		refill := int(elapsed / b.every)
+		b.tokens = min(b.burst, b.tokens+refill)
+		b.last = b.last.Add(time.Duration(refill) * b.every)
+	}
+
+	if b.tokens > 0 {
+		b.tokens--
+
+		return 0
+	}
+
+	return b.every - now.Sub(b.last)
+}
+
+func RateLimitFunc[T any](
+	clock Clock,
+	every time.Duration,
+	burst int,
+) (func(context.Context, chan T, chan T) error, error) {
+	if every <= 0 {
+		return nil, fmt.Errorf("%w: got %s", ErrInvalidRate, every)
+	}
+
+	if burst < 1 {
+		return nil, fmt.Errorf("%w: got %d", ErrInvalidBurst, burst)
+	}
+
+	return func(ctx context.Context, input chan T, output chan T) error {
+		bucket := newTokenBucket(clock.Now(), every, burst)
+
+		timer := clock.NewTimer(every)
+		timer.Stop()
+
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case data, ok := <-input:
+				if !ok {
+					return nil
+				}
+
+				for wait := bucket.take(clock.Now()); wait > 0; wait = bucket.take(clock.Now()) {
+					timer.Reset(wait)
+
+					select {
+					case <-ctx.Done():
+						return nil
+					case <-timer.C():
+					}
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case output <- data:
+				}
+			}
+		}
+	}, nil
+}
+
+func ThrottleFunc[T any](clock Clock, interval time.Duration) (func(context.Context, chan T, chan T) error, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: got %s", ErrInvalidThrottle, interval)
+	}
+
+	return func(ctx context.Context, input chan T, output chan T) error {
+		var (
+			last    time.Time
+			emitted bool
+		)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case data, ok := <-input:
+				if !ok {
+					return nil
+				}
+
+				now := clock.Now()
+				if emitted && now.Sub(last) < interval {
+					continue
+				}
+
+				last, emitted = now, true
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case output <- data:
+				}
+			}
+		}
+	}, nil
+}
+
+func DebounceFunc[T any](clock Clock, quiet time.Duration) (func(context.Context, chan T, chan T) error, error) {
+	if quiet <= 0 {
+		return nil, fmt.Errorf("%w: got %s", ErrInvalidDebounce, quiet)
+	}
+
+	return func(ctx context.Context, input chan T, output chan T) error {
+		var (
+			pending T
+			waiting bool
+		)
+
+		timer := clock.NewTimer(quiet)
+		timer.Stop()
+
+		defer timer.Stop()
+
+		flush := func() bool {
+			if !waiting {
+				return true
+			}
+
+			waiting = false
+
+			select {
+			case <-ctx.Done():
+				return false
+			case output <- pending:
+				return true
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-timer.C():
+				if !flush() {
+					return nil
+				}
+			case data, ok := <-input:
+				if !ok {
+					flush()
+
+					return nil
+				}
+
+				pending, waiting = data, true
+				timer.Reset(quiet)
+			}
+		}
+	}, nil
+}
@@ -0,0 +1,208 @@
+package handlers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rateEvery    = time.Second
+	rateWait     = time.Second
+	ratePoll     = time.Millisecond
+	throttleStep = 400 * time.Millisecond
+)
+
+var epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type scriptedClock struct {
+	*handlers.ManualClock
+	times []time.Time
+}
+
+func (c *scriptedClock) Now() time.Time {
+	now := c.times[0]
+	c.times = c.times[1:]
+
+	return now
+}
+
+func awaitResets(t *testing.T, clock *handlers.ManualClock, resets int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		return clock.Resets() == resets
+	}, rateWait, ratePoll)
+}
+
+func TestRateLimitFuncWaitsForTokens(t *testing.T) {
+	t.Parallel()
+
+	clock := handlers.NewManualClock(epoch)
+	input := make(chan string)
+	output := make(chan string, testChanSize)
+	done := make(chan error, 1)
+
+	limit, err := handlers.RateLimitFunc[string](clock, rateEvery, 2)
+	require.NoError(t, err)
+
+	go func() {
+		done <- limit(context.Background(), input, output)
+	}()
+
+	for _, data := range []string{"a", "b", "c"} {
+		input <- data
+	}
+
+	awaitResets(t, clock, 1)
+	require.Equal(t, []string{"a", "b"}, collect(output))
+
+	clock.Advance(rateEvery)
+	require.Equal(t, "c", <-output)
+
+	close(input)
+	require.NoError(t, <-done)
+}
+
+func TestRateLimitFuncRejectsInvalidSettings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		build func() (func(context.Context, chan string, chan string) error, error)
+		err   error
+	}{
+		{
+			name: "zero interval",
+			build: func() (func(context.Context, chan string, chan string) error, error) {
+				return handlers.RateLimitFunc[string](handlers.NewManualClock(epoch), 0, 1)
+			},
+			err: handlers.ErrInvalidRate,
+		},
+		{
+			name: "negative interval",
+			build: func() (func(context.Context, chan string, chan string) error, error) {
+				return handlers.RateLimitFunc[string](handlers.NewManualClock(epoch), -rateEvery, 1)
+			},
+			err: handlers.ErrInvalidRate,
+		},
+		{
+			name: "zero burst",
+			build: func() (func(context.Context, chan string, chan string) error, error) {
+				return handlers.RateLimitFunc[string](handlers.NewManualClock(epoch), rateEvery, 0)
+			},
+			err: handlers.ErrInvalidBurst,
+		},
+		{
+			name: "zero throttle interval",
+			build: func() (func(context.Context, chan string, chan string) error, error) {
+				return handlers.ThrottleFunc[string](handlers.NewManualClock(epoch), 0)
+			},
+			err: handlers.ErrInvalidThrottle,
+		},
+		{
+			name: "negative debounce quiet period",
+			build: func() (func(context.Context, chan string, chan string) error, error) {
+				return handlers.DebounceFunc[string](handlers.NewManualClock(epoch), -time.Second)
+			},
+			err: handlers.ErrInvalidDebounce,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := test.build()
+			require.ErrorIs(t, err, test.err)
+			require.Nil(t, handler)
+		})
+	}
+}
+
+func TestThrottleFuncDropsWithinInterval(t *testing.T) {
+	t.Parallel()
+
+	clock := &scriptedClock{
+		ManualClock: handlers.NewManualClock(epoch),
+		times: []time.Time{
+			epoch,
+			epoch.Add(throttleStep),
+			epoch.Add(2 * throttleStep),
+			epoch.Add(3 * throttleStep),
+			epoch.Add(4 * throttleStep),
+		},
+	}
+	output := make(chan string, testChanSize)
+
+	throttle, err := handlers.ThrottleFunc[string](clock, time.Second)
+	require.NoError(t, err)
+
+	err = throttle(context.Background(), feed("a", "b", "c", "d", "e"), output)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "d"}, collect(output))
+}
+
+func TestDebounceFuncEmitsLastValueAfterQuiet(t *testing.T) {
+	t.Parallel()
+
+	clock := handlers.NewManualClock(epoch)
+	input := make(chan string)
+	output := make(chan string, testChanSize)
+	done := make(chan error, 1)
+
+	debounce, err := handlers.DebounceFunc[string](clock, time.Second)
+	require.NoError(t, err)
+
+	go func() {
+		done <- debounce(context.Background(), input, output)
+	}()
+
+	input <- "a"
+	awaitResets(t, clock, 1)
+	clock.Advance(time.Second / 2)
+
+	input <- "b"
+	awaitResets(t, clock, 2)
+	clock.Advance(time.Second / 2)
+	require.Empty(t, collect(output))
+
+	clock.Advance(time.Second / 2)
+	require.Equal(t, "b", <-output)
+
+	input <- "c"
+	close(input)
+	require.NoError(t, <-done)
+	require.Equal(t, []string{"c"}, collect(output))
+}
+
+func TestRegisterRateLimit(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.New(testChanSize)
+	handlers.RegisterRateLimit(conv, handlers.SystemClock(), time.Millisecond, 1, "in", "out")
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conv.Run(context.Background())
+	}()
+
+	for _, data := range []string{"a", "b", "c"} {
+		require.NoError(t, conv.Send("in", data))
+	}
+
+	for _, expected := range []string{"a", "b", "c"} {
+		data, err := conv.Recv("out")
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	}
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
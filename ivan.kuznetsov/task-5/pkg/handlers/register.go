@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+)
+
+func RegisterFilter[T any](
+	conv conveyer.Conveyer[T],
+	keep func(T) bool,
+	input string,
+	output string,
+	opts ...conveyer.HandlerOption,
+) {
+	conv.RegisterDecorator(FilterFunc(keep), input, output, opts...)
+}
+
+func RegisterBroadcast[T any](
+	conv conveyer.Conveyer[T],
+	input string,
+	outputs []string,
+	opts ...conveyer.HandlerOption,
+) {
+	conv.RegisterSeparator(BroadcastFunc[T], input, outputs, opts...)
+}
+
+func RegisterBatch[T any](
+	conv conveyer.Conveyer[T],
+	clock Clock,
+	size int,
+	interval time.Duration,
+	join func([]T) T,
+	input string,
+	output string,
+	opts ...conveyer.HandlerOption,
+) error {
+	batch, err := BatchFunc(clock, size, interval, join)
+	if err != nil {
+		return err
+	}
+
+	conv.RegisterDecorator(batch, input, output, opts...)
+
+	return nil
+}
+
+func RegisterSortedMerge[T any](
+	conv conveyer.Conveyer[T],
+	compare func(T, T) int,
+	inputs []string,
+	output string,
+	opts ...conveyer.HandlerOption,
+) {
+	conv.RegisterMultiplexer(SortedMergeFunc(compare), inputs, output, opts...)
+}
+
+func RegisterPriorityMultiplexer[T any](
+	conv conveyer.Conveyer[T],
+	inputs []string,
+	output string,
+	priorityOpts []PriorityOption,
+	opts ...conveyer.HandlerOption,
+) {
+	conv.RegisterMultiplexer(PriorityMultiplexerFunc[T](priorityOpts...), inputs, output, opts...)
+}
+
+func RegisterKeySeparator[T any](
+	conv conveyer.Conveyer[T],
+	key func(T) string,
+	input string,
+	outputs []string,
+	opts ...conveyer.HandlerOption,
+) {
+	conv.RegisterSeparator(KeySeparatorFunc(key), input, outputs, opts...)
+}
+
+func RegisterRateLimit[T any](
+	conv conveyer.Conveyer[T],
+	clock Clock,
+	every time.Duration,
+	burst int,
+	input string,
+	output string,
+	opts ...conveyer.HandlerOption,
+) error {
+	limit, err := RateLimitFunc[T](clock, every, burst)
+	if err != nil {
+		return err
+	}
+
+	conv.RegisterDecorator(limit, input, output, opts...)
+
+	return nil
+}
+
+func RegisterThrottle[T any](
+	conv conveyer.Conveyer[T],
+	clock Clock,
+	interval time.Duration,
+	input string,
+	output string,
+	opts ...conveyer.HandlerOption,
+) error {
+	throttle, err := ThrottleFunc[T](clock, interval)
+	if err != nil {
+		return err
+	}
+
+	conv.RegisterDecorator(throttle, input, output, opts...)
+
+	return nil
+}
+
+func RegisterDebounce[T any](
+	conv conveyer.Conveyer[T],
+	clock Clock,
+	quiet time.Duration,
+	input string,
+	output string,
+	opts ...conveyer.HandlerOption,
+) error {
+	debounce, err := DebounceFunc[T](clock, quiet)
+	if err != nil {
+		return err
+	}
+
+	conv.RegisterDecorator(debounce, input, output, opts...)
+
+	return nil
+}
+
+func RegisterJoin[T any, K comparable](
+	conv conveyer.Conveyer[T],
+	clock Clock,
+	window Window,
+	leftKey func(T) K,
+	rightKey func(T) K,
+	combine func(left T, right T) T,
+	inputs [2]string,
+	outputs []string,
+	opts ...conveyer.HandlerOption,
+) {
+	conv.RegisterStage(JoinFunc(clock, window, leftKey, rightKey, combine), inputs[:], outputs, opts...)
+}
@@ -2,7 +2,7 @@ package handlers
 
 import "context"
 
-func SeparatorFunc(ctx context.Context, input chan string, outputs []chan string) error {
+func GenericSeparatorFunc[T any](ctx context.Context, input chan T, outputs []chan T) error {
 	index := 0
 
 	for {
@@ -23,3 +23,7 @@ func SeparatorFunc(ctx context.Context, input chan string, outputs []chan string
 		}
 	}
 }
+
+func SeparatorFunc(ctx context.Context, input chan string, outputs []chan string) error {
+	return GenericSeparatorFunc(ctx, input, outputs)
+}
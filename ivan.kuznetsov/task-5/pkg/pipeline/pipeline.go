@@ -0,0 +1,315 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidSize    = errors.New("channel size must not be negative")
+	ErrNoHandlers     = errors.New("pipeline has no handlers")
+	ErrUnknownHandler = errors.New("unknown handler")
+	ErrEmptyChannelID = errors.New("empty channel id")
+	ErrInvalidInputs  = errors.New("invalid number of inputs")
+	ErrInvalidOutputs = errors.New("invalid number of outputs")
+	ErrInvalidPolicy  = errors.New("invalid error policy")
+	ErrInvalidChannel = errors.New("invalid channel config")
+)
+
+var overflowPolicies = map[string]conveyer.OverflowPolicy{
+	conveyer.OverflowBlock.String():      conveyer.OverflowBlock,
+	conveyer.OverflowDropNewest.String(): conveyer.OverflowDropNewest,
+	conveyer.OverflowDropOldest.String(): conveyer.OverflowDropOldest,
+	conveyer.OverflowError.String():      conveyer.OverflowError,
+}
+
+const (
+	onErrorFail       = "fail"
+	onErrorSkip       = "skip"
+	onErrorDeadLetter = "dead-letter"
+)
+
+type Config struct {
+	Size     int                      `yaml:"size"`
+	Inputs   []string                 `yaml:"inputs"`
+	Outputs  []string                 `yaml:"outputs"`
+	Channels map[string]ChannelConfig `yaml:"channels"`
+	Handlers []HandlerConfig          `yaml:"handlers"`
+}
+
+type ChannelConfig struct {
+	Capacity *int          `yaml:"capacity"`
+	Overflow string        `yaml:"overflow"`
+	Durable  string        `yaml:"durable"`
+	TTL      time.Duration `yaml:"ttl"`
+	Expired  string        `yaml:"expired"`
+	Priority int           `yaml:"priority"`
+}
+
+type HandlerConfig struct {
+	Name       string        `yaml:"name"`
+	Handler    string        `yaml:"handler"`
+	Inputs     []string      `yaml:"inputs"`
+	Outputs    []string      `yaml:"outputs"`
+	OnError    string        `yaml:"on-error"`
+	DeadLetter string        `yaml:"dead-letter"`
+	Retries    int           `yaml:"retries"`
+	Backoff    time.Duration `yaml:"backoff"`
+	Replicas   int           `yaml:"replicas"`
+	Ordered    bool          `yaml:"ordered"`
+}
+
+func Load[T any](path string, registry *Registry[T]) (conveyer.Conveyer[T], error) {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Build(config, registry)
+}
+
+func LoadConfig(path string) (config *Config, err error) {
+	configFile, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pipeline file: %w", err)
+	}
+
+	defer func() {
+		closeErr := configFile.Close()
+		if closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close pipeline file: %w", closeErr)
+		}
+	}()
+
+	var configData Config
+
+	decoder := yaml.NewDecoder(configFile)
+	decoder.KnownFields(true)
+
+	err = decoder.Decode(&configData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode YAML %w", err)
+	}
+
+	return &configData, nil
+}
+
+func Build[T any](config *Config, registry *Registry[T]) (conveyer.Conveyer[T], error) {
+	if err := validate(config, registry); err != nil {
+		return nil, err
+	}
+
+	conv := conveyer.NewTyped[T](config.Size)
+	conv.DeclareInputs(config.Inputs...)
+	conv.DeclareOutputs(config.Outputs...)
+
+	for id, channelConfig := range config.Channels {
+		if err := conv.ConfigureChannel(id, channelOptions(channelConfig)...); err != nil {
+			return nil, fmt.Errorf("channel %q: %w", id, err)
+		}
+	}
+
+	for _, handlerConfig := range config.Handlers {
+		handlerEntry := registry.entries[handlerConfig.Handler]
+		opts := handlerOptions(handlerConfig)
+
+		switch handlerEntry.kind {
+		case kindDecorator:
+			conv.RegisterDecorator(handlerEntry.fnDecorator, handlerConfig.Inputs[0], handlerConfig.Outputs[0], opts...)
+		case kindMultiplexer:
+			conv.RegisterMultiplexer(handlerEntry.fnMultiplexer, handlerConfig.Inputs, handlerConfig.Outputs[0], opts...)
+		case kindSeparator:
+			conv.RegisterSeparator(handlerEntry.fnSeparator, handlerConfig.Inputs[0], handlerConfig.Outputs, opts...)
+		case kindStage:
+			conv.RegisterStage(handlerEntry.fnStage, handlerConfig.Inputs, handlerConfig.Outputs, opts...)
+		}
+	}
+
+	if err := conv.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid pipeline topology: %w", err)
+	}
+
+	return conv, nil
+}
+
+func validate[T any](config *Config, registry *Registry[T]) error {
+	if config.Size < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidSize, config.Size)
+	}
+
+	if len(config.Handlers) == 0 {
+		return ErrNoHandlers
+	}
+
+	for id, channelConfig := range config.Channels {
+		if err := validateChannel(channelConfig); err != nil {
+			return fmt.Errorf("channel %q: %w", id, err)
+		}
+	}
+
+	for i, handlerConfig := range config.Handlers {
+		if err := validateHandler(handlerConfig, registry); err != nil {
+			return fmt.Errorf("handler %d (%s): %w", i, handlerConfig.Handler, err)
+		}
+	}
+
+	return nil
+}
+
+func validateHandler[T any](handlerConfig HandlerConfig, registry *Registry[T]) error {
+	handlerEntry, ok := registry.entries[handlerConfig.Handler]
+	if !ok {
+		return ErrUnknownHandler
+	}
+
+	for _, id := range append(append([]string{}, handlerConfig.Inputs...), handlerConfig.Outputs...) {
+		if id == "" {
+			return ErrEmptyChannelID
+		}
+	}
+
+	if err := validatePolicy(handlerConfig); err != nil {
+		return err
+	}
+
+	inputs, outputs := len(handlerConfig.Inputs), len(handlerConfig.Outputs)
+
+	switch handlerEntry.kind {
+	case kindDecorator:
+		if inputs != 1 {
+			return fmt.Errorf("%w: decorator expects 1, got %d", ErrInvalidInputs, inputs)
+		}
+
+		if outputs != 1 {
+			return fmt.Errorf("%w: decorator expects 1, got %d", ErrInvalidOutputs, outputs)
+		}
+	case kindMultiplexer:
+		if inputs == 0 {
+			return fmt.Errorf("%w: multiplexer expects at least 1", ErrInvalidInputs)
+		}
+
+		if outputs != 1 {
+			return fmt.Errorf("%w: multiplexer expects 1, got %d", ErrInvalidOutputs, outputs)
+		}
+	case kindSeparator:
+		if inputs != 1 {
+			return fmt.Errorf("%w: separator expects 1, got %d", ErrInvalidInputs, inputs)
+		}
+
+		if outputs == 0 {
+			return fmt.Errorf("%w: separator expects at least 1", ErrInvalidOutputs)
+		}
+	case kindStage:
+		if inputs == 0 {
+			return fmt.Errorf("%w: stage expects at least 1", ErrInvalidInputs)
+		}
+
+		if outputs == 0 {
+			return fmt.Errorf("%w: stage expects at least 1", ErrInvalidOutputs)
+		}
+	}
+
+	return nil
+}
+
+func validatePolicy(handlerConfig HandlerConfig) error {
+	switch handlerConfig.OnError {
+	case "", onErrorFail, onErrorSkip:
+	case onErrorDeadLetter:
+		if handlerConfig.DeadLetter == "" {
+			return fmt.Errorf("%w: dead-letter channel is not set", ErrInvalidPolicy)
+		}
+	default:
+		return fmt.Errorf("%w: unknown on-error %q", ErrInvalidPolicy, handlerConfig.OnError)
+	}
+
+	if handlerConfig.Retries < 0 || handlerConfig.Backoff < 0 {
+		return fmt.Errorf("%w: retries and backoff must not be negative", ErrInvalidPolicy)
+	}
+
+	if handlerConfig.Replicas < 0 {
+		return fmt.Errorf("%w: replicas must not be negative", ErrInvalidPolicy)
+	}
+
+	return nil
+}
+
+func handlerOptions(handlerConfig HandlerConfig) []conveyer.HandlerOption {
+	opts := []conveyer.HandlerOption{
+		conveyer.WithRetry(handlerConfig.Retries, handlerConfig.Backoff),
+	}
+
+	if handlerConfig.Name != "" {
+		opts = append(opts, conveyer.WithName(handlerConfig.Name))
+	}
+
+	if handlerConfig.Replicas > 0 {
+		opts = append(opts, conveyer.WithReplicas(handlerConfig.Replicas))
+	}
+
+	if handlerConfig.Ordered {
+		opts = append(opts, conveyer.WithOrdered())
+	}
+
+	switch handlerConfig.OnError {
+	case onErrorSkip:
+		opts = append(opts, conveyer.WithSkipOnError())
+	case onErrorDeadLetter:
+		opts = append(opts, conveyer.WithDeadLetter(handlerConfig.DeadLetter))
+	default:
+		opts = append(opts, conveyer.WithFailFast())
+	}
+
+	return opts
+}
+
+func validateChannel(channelConfig ChannelConfig) error {
+	if channelConfig.Capacity != nil && *channelConfig.Capacity < 0 {
+		return fmt.Errorf("%w: capacity must not be negative", ErrInvalidChannel)
+	}
+
+	if channelConfig.TTL < 0 {
+		return fmt.Errorf("%w: ttl must not be negative", ErrInvalidChannel)
+	}
+
+	if _, ok := overflowPolicies[channelConfig.Overflow]; channelConfig.Overflow != "" && !ok {
+		return fmt.Errorf("%w: unknown overflow %q", ErrInvalidChannel, channelConfig.Overflow)
+	}
+
+	return nil
+}
+
+func channelOptions(channelConfig ChannelConfig) []conveyer.ChannelOption {
+	var opts []conveyer.ChannelOption
+
+	if channelConfig.Capacity != nil {
+		opts = append(opts, conveyer.WithCapacity(*channelConfig.Capacity))
+	}
+
+	if policy, ok := overflowPolicies[channelConfig.Overflow]; ok {
+		opts = append(opts, conveyer.WithOverflow(policy))
+	}
+
+	if channelConfig.Durable != "" {
+		opts = append(opts, conveyer.WithDurable(channelConfig.Durable))
+	}
+
+	if channelConfig.TTL > 0 {
+		opts = append(opts, conveyer.WithTTL(channelConfig.TTL))
+	}
+
+	if channelConfig.Expired != "" {
+		opts = append(opts, conveyer.WithExpired(channelConfig.Expired))
+	}
+
+	if channelConfig.Priority != 0 {
+		opts = append(opts, conveyer.WithPriority(channelConfig.Priority))
+	}
+
+	return opts
+}
@@ -0,0 +1,157 @@
+package pipeline_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kuzid-17/task-5/pkg/pipeline"
+	"github.com/stretchr/testify/require"
+)
+
+const validPipeline = `
+size: 4
+inputs: [in]
+outputs: [out]
+channels:
+  in:
+    capacity: 8
+    overflow: drop-oldest
+handlers:
+  - handler: prefix-decorator
+    inputs: [in]
+    outputs: [out]
+`
+
+func writePipeline(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "pipeline.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestLoadBuildsRunnablePipeline(t *testing.T) {
+	t.Parallel()
+
+	conv, err := pipeline.Load(writePipeline(t, validPipeline), pipeline.DefaultRegistry())
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conv.Run(context.Background())
+	}()
+
+	require.NoError(t, conv.Send("in", "a"))
+
+	data, err := conv.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "decorated: a", data)
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestLoadRejectsInvalidPipelines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		err     error
+	}{
+		{
+			name: "unknown handler",
+			content: `
+handlers:
+  - handler: no-such-handler
+    inputs: [in]
+    outputs: [out]
+`,
+			err: pipeline.ErrUnknownHandler,
+		},
+		{
+			name: "negative capacity",
+			content: `
+channels:
+  in:
+    capacity: -1
+handlers:
+  - handler: prefix-decorator
+    inputs: [in]
+    outputs: [out]
+`,
+			err: pipeline.ErrInvalidChannel,
+		},
+		{
+			name: "unknown overflow",
+			content: `
+channels:
+  in:
+    overflow: spill
+handlers:
+  - handler: prefix-decorator
+    inputs: [in]
+    outputs: [out]
+`,
+			err: pipeline.ErrInvalidChannel,
+		},
+		{
+			name: "decorator with two outputs",
+			content: `
+handlers:
+  - handler: prefix-decorator
+    inputs: [in]
+    outputs: [a, b]
+`,
+			err: pipeline.ErrInvalidOutputs,
+		},
+		{
+			name:    "no handlers",
+			content: "size: 1\n",
+			err:     pipeline.ErrNoHandlers,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := pipeline.Load(writePipeline(t, test.content), pipeline.DefaultRegistry())
+			require.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func TestLoadConfigRejectsMalformedYAML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "broken syntax", content: "handlers: [\n"},
+		{name: "unknown field", content: "workers: 3\n"},
+		{name: "wrong type", content: "size: many\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := pipeline.LoadConfig(writePipeline(t, test.content))
+			require.Error(t, err)
+			require.Nil(t, config)
+		})
+	}
+}
+
+func TestLoadConfigReportsMissingFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := pipeline.LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,101 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kuzid-17/task-5/pkg/handlers"
+)
+
+var ErrDuplicateHandler = errors.New("handler already registered")
+
+type handlerKind string
+
+const (
+	kindDecorator   handlerKind = "decorator"
+	kindMultiplexer handlerKind = "multiplexer"
+	kindSeparator   handlerKind = "separator"
+	kindStage       handlerKind = "stage"
+)
+
+type entry[T any] struct {
+	kind handlerKind
+
+	fnDecorator   func(context.Context, chan T, chan T) error
+	fnMultiplexer func(context.Context, []chan T, chan T) error
+	fnSeparator   func(context.Context, chan T, []chan T) error
+	fnStage       func(context.Context, []chan T, []chan T) error
+}
+
+type Registry[T any] struct {
+	entries map[string]entry[T]
+}
+
+func NewRegistry[T any]() *Registry[T] {
+	return &Registry[T]{
+		entries: make(map[string]entry[T]),
+	}
+}
+
+func DefaultRegistry() *Registry[string] {
+	registry := NewRegistry[string]()
+
+	_ = registry.AddDecorator("prefix-decorator", handlers.PrefixDecoratorFunc)
+	_ = registry.AddMultiplexer("multiplexer", handlers.MultiplexerFunc)
+	_ = registry.AddMultiplexer("priority-multiplexer", handlers.PriorityMultiplexerFunc[string]())
+	_ = registry.AddSeparator("separator", handlers.SeparatorFunc)
+	_ = registry.AddSeparator("broadcast", handlers.BroadcastFunc[string])
+
+	return registry
+}
+
+func (r *Registry[T]) AddDecorator(name string, fn func(context.Context, chan T, chan T) error) error {
+	return r.add(name, entry[T]{
+		kind:          kindDecorator,
+		fnDecorator:   fn,
+		fnMultiplexer: nil,
+		fnSeparator:   nil,
+		fnStage:       nil,
+	})
+}
+
+func (r *Registry[T]) AddMultiplexer(name string, fn func(context.Context, []chan T, chan T) error) error {
+	return r.add(name, entry[T]{
+		kind:          kindMultiplexer,
+		fnDecorator:   nil,
+		fnMultiplexer: fn,
+		fnSeparator:   nil,
+		fnStage:       nil,
+	})
+}
+
+func (r *Registry[T]) AddSeparator(name string, fn func(context.Context, chan T, []chan T) error) error {
+	return r.add(name, entry[T]{
+		kind:          kindSeparator,
+		fnDecorator:   nil,
+		fnMultiplexer: nil,
+		fnSeparator:   fn,
+		fnStage:       nil,
+	})
+}
+
+func (r *Registry[T]) AddStage(name string, fn func(context.Context, []chan T, []chan T) error) error {
+	return r.add(name, entry[T]{
+		kind:          kindStage,
+		fnDecorator:   nil,
+		fnMultiplexer: nil,
+		fnSeparator:   nil,
+		fnStage:       fn,
+	})
+}
+
+func (r *Registry[T]) add(name string, handlerEntry entry[T]) error {
+	if _, ok := r.entries[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateHandler, name)
+	}
+
+	r.entries[name] = handlerEntry
+
+	return nil
+}
@@ -0,0 +1,268 @@
+package tracing
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/errpolicy"
+)
+
+const maxPending = 1024
+
+type (
+	EnvelopeDecorator[T any]   func(context.Context, chan *Envelope[T], chan *Envelope[T]) error
+	EnvelopeMultiplexer[T any] func(context.Context, []chan *Envelope[T], chan *Envelope[T]) error
+	EnvelopeSeparator[T any]   func(context.Context, chan *Envelope[T], []chan *Envelope[T]) error
+)
+
+func Decorator[T any](fn func(context.Context, chan T, chan T) error) EnvelopeDecorator[T] {
+	return func(ctx context.Context, input chan *Envelope[T], output chan *Envelope[T]) error {
+		return adapt(ctx, []chan *Envelope[T]{input}, []chan *Envelope[T]{output},
+			func(ctx context.Context, inputs []chan T, outputs []chan T) error {
+				return fn(ctx, inputs[0], outputs[0])
+			})
+	}
+}
+
+func Multiplexer[T any](fn func(context.Context, []chan T, chan T) error) EnvelopeMultiplexer[T] {
+	return func(ctx context.Context, inputs []chan *Envelope[T], output chan *Envelope[T]) error {
+		return adapt(ctx, inputs, []chan *Envelope[T]{output},
+			func(ctx context.Context, inputs []chan T, outputs []chan T) error {
+				return fn(ctx, inputs, outputs[0])
+			})
+	}
+}
+
+func Separator[T any](fn func(context.Context, chan T, []chan T) error) EnvelopeSeparator[T] {
+	return func(ctx context.Context, input chan *Envelope[T], outputs []chan *Envelope[T]) error {
+		return adapt(ctx, []chan *Envelope[T]{input}, outputs,
+			func(ctx context.Context, inputs []chan T, outputs []chan T) error {
+				return fn(ctx, inputs[0], outputs)
+			})
+	}
+}
+
+func Map[T any](fn func(T) (T, error)) EnvelopeDecorator[T] {
+	return func(ctx context.Context, input chan *Envelope[T], output chan *Envelope[T]) error {
+		handler := conveyer.HandlerName(ctx)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case envelope, ok := <-input:
+				if !ok {
+					return nil
+				}
+
+				start := time.Now()
+
+				payload, ok, err := conveyer.Apply(ctx, envelope.Payload, fn)
+				if err != nil {
+					return err
+				}
+
+				if !ok {
+					continue
+				}
+
+				hop := envelope.Hop(payload, Span{Handler: handler, Start: start, End: time.Now()})
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case output <- hop:
+				}
+			}
+		}
+	}
+}
+
+func adapt[T any](
+	ctx context.Context,
+	inputs []chan *Envelope[T],
+	outputs []chan *Envelope[T],
+	run func(context.Context, []chan T, []chan T) error,
+) error {
+	r := newRelay(ctx, conveyer.HandlerName(ctx), inputs, outputs)
+
+	go func() {
+		r.done <- errpolicy.Guard(ctx, func() error {
+			return run(ctx, r.plainInputs, r.plainOutputs)
+		})
+	}()
+
+	return r.serve(ctx)
+}
+
+type consumed[T any] struct {
+	envelope *Envelope[T]
+	lane     int
+	start    time.Time
+}
+
+type relay[T any] struct {
+	handler      string
+	outputs      []chan *Envelope[T]
+	plainInputs  []chan T
+	plainOutputs []chan T
+	done         chan error
+	receives     []reflect.Value
+	cases        []reflect.SelectCase
+	held         consumed[T]
+	pending      []consumed[T]
+	last         *consumed[T]
+}
+
+func newRelay[T any](
+	ctx context.Context,
+	handler string,
+	inputs []chan *Envelope[T],
+	outputs []chan *Envelope[T],
+) *relay[T] {
+	r := &relay[T]{
+		handler:      handler,
+		outputs:      outputs,
+		plainInputs:  make([]chan T, len(inputs)),
+		plainOutputs: make([]chan T, len(outputs)),
+		done:         make(chan error, 1),
+		receives:     make([]reflect.Value, len(inputs)),
+		cases:        make([]reflect.SelectCase, 0, 2*len(inputs)+len(outputs)+2),
+		held:         consumed[T]{envelope: nil, lane: 0, start: time.Time{}},
+		pending:      nil,
+		last:         nil,
+	}
+
+	r.cases = append(r.cases,
+		reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done()), Send: reflect.Value{}},
+		reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(r.done), Send: reflect.Value{}},
+	)
+
+	for i, input := range inputs {
+		r.receives[i] = reflect.ValueOf(input)
+		r.cases = append(r.cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: r.receives[i], Send: reflect.Value{}})
+	}
+
+	for i := range r.plainInputs {
+		r.plainInputs[i] = make(chan T)
+		r.cases = append(r.cases, reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.Value{}, Send: reflect.Value{}})
+	}
+
+	for i := range r.plainOutputs {
+		r.plainOutputs[i] = make(chan T)
+		r.cases = append(r.cases, reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(r.plainOutputs[i]),
+			Send: reflect.Value{},
+		})
+	}
+
+	return r
+}
+
+func (r *relay[T]) serve(ctx context.Context) error {
+	lanes := len(r.plainInputs)
+
+	for {
+		chosen, value, ok := reflect.Select(r.cases)
+
+		switch {
+		case chosen == 0:
+			<-r.done
+
+			return nil
+		case chosen == 1:
+			err, _ := value.Interface().(error)
+
+			return err
+		case chosen < lanes+2:
+			r.receive(chosen-2, value, ok)
+		case chosen < 2*lanes+2:
+			r.fed(chosen - lanes - 2)
+		default:
+			r.emit(ctx, chosen-2*lanes-2, value)
+		}
+	}
+}
+
+func (r *relay[T]) receive(lane int, value reflect.Value, ok bool) {
+	if !ok {
+		r.receives[lane] = reflect.Value{}
+		r.cases[lane+2].Chan = reflect.Value{}
+		close(r.plainInputs[lane])
+
+		return
+	}
+
+	envelope, _ := value.Interface().(*Envelope[T])
+	r.held = consumed[T]{envelope: envelope, lane: lane, start: time.Now()}
+
+	for i := range r.receives {
+		r.cases[i+2].Chan = reflect.Value{}
+	}
+
+	feed := &r.cases[len(r.receives)+lane+2]
+	feed.Chan = reflect.ValueOf(r.plainInputs[lane])
+	feed.Send = reflect.ValueOf(&envelope.Payload).Elem()
+}
+
+func (r *relay[T]) fed(lane int) {
+	feed := &r.cases[len(r.receives)+lane+2]
+	feed.Chan, feed.Send = reflect.Value{}, reflect.Value{}
+
+	r.pending = append(r.pending, r.held)
+	if len(r.pending) > maxPending {
+		r.pending = slices.Delete(r.pending, 0, 1)
+	}
+
+	for i, receive := range r.receives {
+		r.cases[i+2].Chan = receive
+	}
+}
+
+func (r *relay[T]) emit(ctx context.Context, output int, value reflect.Value) {
+	payload, _ := value.Interface().(T)
+	origin := r.attribute(payload)
+	hop := origin.envelope.Hop(payload, Span{Handler: r.handler, Start: origin.start, End: time.Now()})
+
+	select {
+	case <-ctx.Done():
+	case r.outputs[output] <- hop:
+	}
+}
+
+func (r *relay[T]) attribute(payload T) consumed[T] {
+	if len(r.pending) == 0 {
+		if r.last != nil {
+			return *r.last
+		}
+
+		return consumed[T]{envelope: Wrap(payload, nil), lane: 0, start: time.Now()}
+	}
+
+	index := slices.IndexFunc(r.pending, func(candidate consumed[T]) bool {
+		return reflect.DeepEqual(candidate.envelope.Payload, payload)
+	})
+	if index < 0 {
+		index = len(r.pending) - 1
+	}
+
+	origin := r.pending[index]
+	kept := r.pending[:0]
+
+	for i, candidate := range r.pending {
+		if i == index || (i < index && candidate.lane == origin.lane) {
+			continue
+		}
+
+		kept = append(kept, candidate)
+	}
+
+	r.pending = kept
+	r.last = &origin
+
+	return origin
+}
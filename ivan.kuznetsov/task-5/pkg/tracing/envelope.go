@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+)
+
+const idBytes = 16
+
+type Envelope[T any] struct {
+	ID      string            `json:"id"`
+	Created time.Time         `json:"created"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Spans   []Span            `json:"spans"`
+	Payload T                 `json:"payload"`
+}
+
+type Span struct {
+	Handler string    `json:"handler"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+func Wrap[T any](payload T, headers map[string]string) *Envelope[T] {
+	return &Envelope[T]{
+		ID:      newID(),
+		Created: time.Now(),
+		Headers: maps.Clone(headers),
+		Spans:   nil,
+		Payload: payload,
+	}
+}
+
+func (e *Envelope[T]) Hop(payload T, span Span) *Envelope[T] {
+	return &Envelope[T]{
+		ID:      e.ID,
+		Created: e.Created,
+		Headers: maps.Clone(e.Headers),
+		Spans:   append(slices.Clip(e.Spans), span),
+		Payload: payload,
+	}
+}
+
+func Send[T any](ctx context.Context, conv conveyer.Conveyer[*Envelope[T]], input string, payload T, headers map[string]string) (string, error) {
+	envelope := Wrap(payload, headers)
+
+	if err := conv.SendContext(ctx, input, envelope); err != nil {
+		return "", fmt.Errorf("send envelope: %w", err)
+	}
+
+	return envelope.ID, nil
+}
+
+func newID() string {
+	id := make([]byte, idBytes)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+)
+
+type Exporter[T any] interface {
+	Export(output string, envelope *Envelope[T]) error
+}
+
+type JSONLinesExporter[T any] struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+type traceRecord[T any] struct {
+	*Envelope[T]
+
+	Output    string    `json:"output"`
+	Completed time.Time `json:"completed"`
+}
+
+func NewJSONLinesExporter[T any](w io.Writer) *JSONLinesExporter[T] {
+	return &JSONLinesExporter[T]{
+		mu:      sync.Mutex{},
+		encoder: json.NewEncoder(w),
+	}
+}
+
+func (e *JSONLinesExporter[T]) Export(output string, envelope *Envelope[T]) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	record := traceRecord[T]{
+		Envelope:  envelope,
+		Output:    output,
+		Completed: time.Now(),
+	}
+
+	if err := e.encoder.Encode(record); err != nil {
+		return fmt.Errorf("export trace %s: %w", envelope.ID, err)
+	}
+
+	return nil
+}
+
+func Recv[T any](ctx context.Context, conv conveyer.Conveyer[*Envelope[T]], output string, exporter Exporter[T]) (T, error) {
+	var zero T
+
+	envelope, err := conv.RecvContext(ctx, output)
+	if err != nil {
+		return zero, fmt.Errorf("recv envelope: %w", err)
+	}
+
+	if err := exporter.Export(output, envelope); err != nil {
+		return zero, err
+	}
+
+	return envelope.Payload, nil
+}
@@ -0,0 +1,224 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kuzid-17/task-5/pkg/conveyer"
+	"github.com/kuzid-17/task-5/pkg/handlers"
+	"github.com/kuzid-17/task-5/pkg/tracing"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testChanSize  = 8
+	testBatchSize = 2
+)
+
+type exportedTrace struct {
+	ID      string            `json:"id"`
+	Output  string            `json:"output"`
+	Headers map[string]string `json:"headers"`
+	Payload string            `json:"payload"`
+	Spans   []tracing.Span    `json:"spans"`
+}
+
+func TestTracesFollowMessagesAcrossHops(t *testing.T) {
+	t.Parallel()
+
+	conv := conveyer.NewTyped[*tracing.Envelope[string]](testChanSize)
+	conv.RegisterDecorator(tracing.Map(func(data string) (string, error) {
+		return strings.ToUpper(data), nil
+	}), "in", "upper", conveyer.WithName("upper"))
+	conv.RegisterDecorator(tracing.Decorator(handlers.PrefixDecoratorFunc), "upper", "prefixed",
+		conveyer.WithName("prefix"), conveyer.WithSkipOnError())
+	conv.RegisterSeparator(tracing.Separator(handlers.SeparatorFunc), "prefixed", []string{"left", "right"},
+		conveyer.WithName("split"))
+	conv.RegisterMultiplexer(tracing.Multiplexer(handlers.MultiplexerFunc), []string{"left", "right"}, "out",
+		conveyer.WithName("join"))
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conv.Run(context.Background())
+	}()
+
+	ids := make(map[string]string)
+
+	for _, data := range []string{"a", "b", "c"} {
+		id, err := tracing.Send(context.Background(), conv, "in", data, map[string]string{"source": "test"})
+		require.NoError(t, err)
+
+		ids["decorated: "+strings.ToUpper(data)] = id
+	}
+
+	var buf bytes.Buffer
+
+	exporter := tracing.NewJSONLinesExporter[string](&buf)
+
+	for range ids {
+		_, err := tracing.Recv(context.Background(), conv, "out", exporter)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, conv.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, len(ids))
+
+	for _, line := range lines {
+		var trace exportedTrace
+		require.NoError(t, json.Unmarshal([]byte(line), &trace))
+
+		require.Equal(t, ids[trace.Payload], trace.ID)
+		require.Equal(t, "out", trace.Output)
+		require.Equal(t, map[string]string{"source": "test"}, trace.Headers)
+		require.Len(t, trace.Spans, 4)
+
+		for i, handler := range []string{"upper", "prefix", "split", "join"} {
+			require.Equal(t, handler, trace.Spans[i].Handler)
+			require.False(t, trace.Spans[i].End.Before(trace.Spans[i].Start))
+		}
+	}
+}
+
+func TestDecoratorAdapterKeepsEnvelopeOfEachMessage(t *testing.T) {
+	t.Parallel()
+
+	input := make(chan *tracing.Envelope[string], testChanSize)
+	output := make(chan *tracing.Envelope[string], testChanSize)
+	sent := make(map[string]string)
+
+	for _, data := range []string{"x", "no decorator", "y", "z"} {
+		envelope := tracing.Wrap(data, nil)
+		sent[data] = envelope.ID
+		input <- envelope
+	}
+
+	close(input)
+
+	err := tracing.Decorator(handlers.FilterFunc(func(data string) bool {
+		return data != "no decorator"
+	}))(context.Background(), input, output)
+	require.NoError(t, err)
+	close(output)
+
+	var payloads []string
+
+	for envelope := range output {
+		payloads = append(payloads, envelope.Payload)
+		require.Equal(t, sent[envelope.Payload], envelope.ID)
+		require.Len(t, envelope.Spans, 1)
+	}
+
+	require.Equal(t, []string{"x", "y", "z"}, payloads)
+}
+
+func TestMultiplexerAdapterKeepsEnvelopesOfEqualPayloads(t *testing.T) {
+	t.Parallel()
+
+	inputs := []chan *tracing.Envelope[string]{
+		make(chan *tracing.Envelope[string], testChanSize),
+		make(chan *tracing.Envelope[string], testChanSize),
+	}
+	output := make(chan *tracing.Envelope[string], testChanSize)
+	sent := make(map[string]bool)
+
+	for _, input := range inputs {
+		for range testChanSize / len(inputs) {
+			envelope := tracing.Wrap("same", nil)
+			sent[envelope.ID] = false
+			input <- envelope
+		}
+
+		close(input)
+	}
+
+	err := tracing.Multiplexer(handlers.MultiplexerFunc)(context.Background(), inputs, output)
+	require.NoError(t, err)
+	close(output)
+
+	for envelope := range output {
+		delivered, ok := sent[envelope.ID]
+		require.True(t, ok)
+		require.False(t, delivered)
+		require.Len(t, envelope.Spans, 1)
+
+		sent[envelope.ID] = true
+	}
+
+	for id, delivered := range sent {
+		require.True(t, delivered, id)
+	}
+}
+
+func TestSeparatorAdapterKeepsHandlerStateAcrossMessages(t *testing.T) {
+	t.Parallel()
+
+	input := make(chan *tracing.Envelope[string], testChanSize)
+	outputs := []chan *tracing.Envelope[string]{
+		make(chan *tracing.Envelope[string], testChanSize),
+		make(chan *tracing.Envelope[string], testChanSize),
+	}
+	sent := make(map[string]string)
+
+	for _, data := range []string{"a", "b", "c", "d"} {
+		envelope := tracing.Wrap(data, nil)
+		sent[data] = envelope.ID
+		input <- envelope
+	}
+
+	close(input)
+
+	require.NoError(t, tracing.Separator(handlers.SeparatorFunc)(context.Background(), input, outputs))
+
+	for i, expected := range [][]string{{"a", "c"}, {"b", "d"}} {
+		close(outputs[i])
+
+		var payloads []string
+
+		for envelope := range outputs[i] {
+			payloads = append(payloads, envelope.Payload)
+			require.Equal(t, sent[envelope.Payload], envelope.ID)
+			require.Len(t, envelope.Spans, 1)
+		}
+
+		require.Equal(t, expected, payloads)
+	}
+}
+
+func TestDecoratorAdapterKeepsBatchesAcrossMessages(t *testing.T) {
+	t.Parallel()
+
+	batch, err := handlers.JoinBatchFunc(handlers.SystemClock(), testBatchSize, time.Minute, ",")
+	require.NoError(t, err)
+
+	input := make(chan *tracing.Envelope[string], testChanSize)
+	output := make(chan *tracing.Envelope[string], testChanSize)
+	sent := make(map[string]string)
+
+	for _, data := range []string{"a", "b", "c", "d"} {
+		envelope := tracing.Wrap(data, nil)
+		sent[data] = envelope.ID
+		input <- envelope
+	}
+
+	close(input)
+
+	require.NoError(t, tracing.Decorator(batch)(context.Background(), input, output))
+	close(output)
+
+	var payloads []string
+
+	for envelope := range output {
+		payloads = append(payloads, envelope.Payload)
+		require.Equal(t, sent[envelope.Payload[len(envelope.Payload)-1:]], envelope.ID)
+	}
+
+	require.Equal(t, []string{"a,b", "c,d"}, payloads)
+}